@@ -42,22 +42,27 @@ var confCmd = &cobra.Command{
 		}
 
 		// print Values
-		fmt.Printf("Registry URI: %s\n", conf.Registry.RegistryURI)
+		for _, reg := range conf.GetRegistries() {
+			fmt.Printf("Registry '%s' URI: %s\n", reg.Name, reg.RegistryURI)
 
-		if b, _ := strconv.ParseBool(conf.Registry.RequireAuth); b {
-			fmt.Printf("Authentication is required\n")
-		} else {
-			fmt.Printf("Authentication is not required\n")
-		}
+			if b, _ := strconv.ParseBool(reg.RequireAuth); b {
+				fmt.Printf("Authentication is required\n")
+			} else {
+				fmt.Printf("Authentication is not required\n")
+			}
 
-		fmt.Printf("Registry Username: %s\n", conf.Registry.Username)
+			fmt.Printf("Registry Username: %s\n", reg.Username)
 
-		if len(conf.Registry.Password) > 0 {
-			fmt.Printf("Registry Password is set.\n")
-		} else {
-			fmt.Printf("Registry Password is not set!\n")
-		}
+			if len(reg.Password) > 0 {
+				fmt.Printf("Registry Password is set.\n")
+			} else {
+				fmt.Printf("Registry Password is not set!\n")
+			}
 
+			if store := conf.GetSigstore(reg); len(store.URI) > 0 {
+				fmt.Printf("Signature Store: %s %s\n", store.Name, store.URI)
+			}
+		}
 	},
 }
 
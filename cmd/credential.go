@@ -0,0 +1,149 @@
+/*
+Copyright © 2019 Emanuel Bennici <eb@fabmation.de>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/apsdehal/go-logger"
+	"github.com/awnumar/memguard"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/fabmation-gmbh/oima/internal"
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/credential"
+	"github.com/fabmation-gmbh/oima/pkg/errors"
+)
+
+// credentialCmd represents the credential command
+var credentialCmd = &cobra.Command{
+	Use:   "credential",
+	Short: "Manage the encrypted Credential Vault",
+	Long: `Manages the encrypted Credential Vault ('credential.vault',
+defaults to '$HOME/.oima.vault').
+
+The Credentials in the Vault are used if they are not set in the
+Configuration File. Known Credential Names are:
+  password               Password of the Registry User
+  s3_accessKeyID         S3 Access Key ID
+  s3_secretAccessKeyID   S3 Secret Access Key
+  notary_password        Password of the Notary User
+
+Credentials of a named Registry ('registries') or Signature Store
+('sigstores') need the Name as Suffix, eg. 'password/<registry>' or
+'s3_accessKeyID/<sigstore>'. Names without Suffix belong to the
+'default' Registry and to the global 's3' Block.
+
+The Passphrase of the Vault is read from the Environment Variable
+OIMA_VAULT_PASSPHRASE or prompted interactively.`,
+
+	// the Vault is opened by the Sub-Commands, so the Credentials
+	// must not be loaded (and the Passphrase not prompted) by the root Command
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if debug { Log.SetLogLevel(logger.DebugLevel) }
+
+		Config = internal.GetConfig()
+	},
+}
+
+// readSecret returns the Value of the Environment Variable @env
+// or prompts the User for it (without Echo)
+func readSecret(env string, prompt string) ([]byte, error) {
+	if v, ok := os.LookupEnv(env); ok { return []byte(v), nil }
+
+	fmt.Print(prompt)
+	secret, err := terminal.ReadPassword(int(syscall.Stdin))
+	fmt.Println()
+
+	return secret, err
+}
+
+// readNewSecret prompts the User twice for a new Secret (or returns the Value of @env)
+func readNewSecret(env string, prompt string) ([]byte, error) {
+	if v, ok := os.LookupEnv(env); ok { return []byte(v), nil }
+
+	secret, err := readSecret(env, prompt)
+	if err != nil { return nil, err }
+
+	confirm, err := readSecret(env, "Repeat " + prompt)
+	if err != nil { memguard.WipeBytes(secret); return nil, err }
+	defer memguard.WipeBytes(confirm)
+
+	if !bytes.Equal(secret, confirm) {
+		memguard.WipeBytes(secret)
+		return nil, errors.NewVaultDecryptErrorMsg("The Passphrases do not match")
+	}
+
+	return secret, nil
+}
+
+// openVault loads the Credential Vault into a new CredStore and returns the Passphrase of the Vault.
+// If the Vault does not exist yet, the User is asked for a new Passphrase.
+// The Passphrase must be wiped by the Caller.
+func openVault() (*credential.CredStore, []byte) {
+	store := new(credential.CredStore)
+	path := Config.VaultPath()
+
+	if !credential.VaultExists(path) {
+		Log.Noticef("Creating new Credential Vault '%s'", path)
+
+		passphrase, err := readNewSecret("OIMA_VAULT_PASSPHRASE", "New Vault Passphrase: ")
+		if err != nil {
+			Log.Errorf("Error while reading Passphrase: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+
+		return store, passphrase
+	}
+
+	passphrase, err := readSecret("OIMA_VAULT_PASSPHRASE", "Vault Passphrase: ")
+	if err != nil {
+		Log.Errorf("Error while reading Passphrase: %s", err.Error())
+		memguard.SafeExit(1)
+	}
+
+	err = store.LoadVault(path, passphrase)
+	if err != nil {
+		memguard.WipeBytes(passphrase)
+		Log.Errorf("Error while opening Credential Vault '%s': %s", path, err.Error())
+		memguard.SafeExit(1)
+	}
+
+	return store, passphrase
+}
+
+// saveVault writes the CredStore encrypted to the Credential Vault
+func saveVault(store *credential.CredStore, passphrase []byte) {
+	err := store.SaveVault(Config.VaultPath(), passphrase)
+	if err != nil {
+		Log.Errorf("Error while writing Credential Vault '%s': %s", Config.VaultPath(), err.Error())
+		memguard.SafeExit(1)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(credentialCmd)
+}
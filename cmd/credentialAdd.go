@@ -0,0 +1,85 @@
+/*
+Copyright © 2019 Emanuel Bennici <eb@fabmation.de>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/awnumar/memguard"
+	"github.com/spf13/cobra"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+)
+
+var credentialAddForce bool		// Overwrite an existing Credential
+
+// credentialAddCmd represents the credential add command
+var credentialAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a Credential to the Vault",
+	Long: `Adds a Credential to the encrypted Vault. The Secret is read from
+the Environment Variable OIMA_CREDENTIAL_SECRET or prompted interactively.`,
+	Args: cobra.ExactArgs(1),
+
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+
+		store, passphrase := openVault()
+		defer memguard.WipeBytes(passphrase)
+
+		if _, err := store.GetCredential(name); err == nil {
+			if !credentialAddForce {
+				Log.Errorf("Credential '%s' already exists in the Vault (use --force to overwrite it)", name)
+				memguard.SafeExit(1)
+			}
+
+			_ = store.RemoveCredential(name)
+		}
+
+		secret, err := readSecret("OIMA_CREDENTIAL_SECRET", fmt.Sprintf("Secret for '%s': ", name))
+		if err != nil {
+			Log.Errorf("Error while reading Secret: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+
+		if len(secret) == 0 {
+			Log.Error("The Secret must not be empty")
+			memguard.SafeExit(1)
+		}
+
+		// AddCredential wipes the Secret
+		err = store.AddCredential(name, secret)
+		if err != nil {
+			Log.Error(err.Error())
+			memguard.SafeExit(1)
+		}
+
+		saveVault(store, passphrase)
+		fmt.Printf("Added Credential '%s'\n", name)
+	},
+}
+
+func init() {
+	credentialCmd.AddCommand(credentialAddCmd)
+
+	credentialAddCmd.Flags().BoolVar(&credentialAddForce, "force", false, "Overwrite the Credential if it already exists")
+}
@@ -0,0 +1,55 @@
+/*
+Copyright © 2019 Emanuel Bennici <eb@fabmation.de>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/awnumar/memguard"
+	"github.com/spf13/cobra"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/credential"
+)
+
+// credentialListCmd represents the credential list command
+var credentialListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the Names of all Credentials in the Vault",
+	Args:  cobra.NoArgs,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		if !credential.VaultExists(Config.VaultPath()) {
+			Log.Errorf("Credential Vault '%s' does not exist", Config.VaultPath())
+			memguard.SafeExit(1)
+		}
+
+		store, passphrase := openVault()
+		memguard.WipeBytes(passphrase)
+
+		for _, name := range store.Names() { fmt.Println(name) }
+	},
+}
+
+func init() {
+	credentialCmd.AddCommand(credentialListCmd)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright © 2019 Emanuel Bennici <eb@fabmation.de>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/awnumar/memguard"
+	"github.com/spf13/cobra"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/credential"
+)
+
+// credentialRemoveCmd represents the credential remove command
+var credentialRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a Credential from the Vault",
+	Args:  cobra.ExactArgs(1),
+
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+
+		if !credential.VaultExists(Config.VaultPath()) {
+			Log.Errorf("Credential Vault '%s' does not exist", Config.VaultPath())
+			memguard.SafeExit(1)
+		}
+
+		store, passphrase := openVault()
+		defer memguard.WipeBytes(passphrase)
+
+		err := store.RemoveCredential(name)
+		if err != nil {
+			Log.Errorf("Credential '%s' does not exist in the Vault", name)
+			memguard.SafeExit(1)
+		}
+
+		saveVault(store, passphrase)
+		fmt.Printf("Removed Credential '%s'\n", name)
+	},
+}
+
+func init() {
+	credentialCmd.AddCommand(credentialRemoveCmd)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2019 Emanuel Bennici <eb@fabmation.de>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/awnumar/memguard"
+	"github.com/spf13/cobra"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/credential"
+)
+
+// credentialRotateCmd represents the credential rotate command
+var credentialRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Change the Passphrase of the Vault",
+	Long: `Re-encrypts the Vault with a new Passphrase (and a new Salt).
+The new Passphrase is read from the Environment Variable
+OIMA_VAULT_NEW_PASSPHRASE or prompted interactively.`,
+	Args: cobra.NoArgs,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		if !credential.VaultExists(Config.VaultPath()) {
+			Log.Errorf("Credential Vault '%s' does not exist", Config.VaultPath())
+			memguard.SafeExit(1)
+		}
+
+		store, passphrase := openVault()
+		memguard.WipeBytes(passphrase)
+
+		newPassphrase, err := readNewSecret("OIMA_VAULT_NEW_PASSPHRASE", "New Vault Passphrase: ")
+		if err != nil {
+			Log.Errorf("Error while reading Passphrase: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+		defer memguard.WipeBytes(newPassphrase)
+
+		saveVault(store, newPassphrase)
+		fmt.Println("Vault Passphrase changed")
+	},
+}
+
+func init() {
+	credentialCmd.AddCommand(credentialRotateCmd)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright © 2019 Emanuel Bennici <eb@fabmation.de>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/awnumar/memguard"
+	"github.com/spf13/cobra"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/registry"
+)
+
+var (
+	imageDeleteDryRun	bool		// Only print what would be deleted
+	imageDeleteYes		bool		// Do not ask before deleting a Manifest which is shared by more than one Tag
+)
+
+// imageDeleteCmd represents the image delete command
+var imageDeleteCmd = &cobra.Command{
+	Use:   "delete <image>:<tag> | <image>@sha256:<digest>",
+	Short: "Delete the Manifest of an Image Tag (and its Signatures)",
+	Long: `Resolves the Digest of an Image Tag and deletes the Manifest from the Registry
+('DELETE /v2/<name>/manifests/<digest>'). The Signatures of the Digest are deleted
+from the Signature Store of the Registry and all named Signature Stores, which
+are not bound to another Registry (read-only Stores are skipped), and the Trust
+Data from the Notary Server.
+
+The Registry deletes every Tag which points to the same Digest, so the Deletion
+has to be confirmed (or --yes has to be set) if other Tags share the Digest.
+Deleting Manifests must be enabled in the Registry
+(eg. 'REGISTRY_STORAGE_DELETE_ENABLED=true').`,
+	Args: cobra.ExactArgs(1),
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		imageName, tagName, digest, err := registry.ParseReference(args[0])
+		if err != nil {
+			Log.Error(err.Error())
+			memguard.SafeExit(1)
+		}
+
+		var dockerRegistry registry.DockerRegistry
+		dockerRegistry.Name = registryName
+		err = dockerRegistry.Init(ctx)
+		if err != nil {
+			Log.Fatalf("Error while Initialize DockerRegistry: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+
+		img, err := dockerRegistry.GetImage(ctx, imageName)
+		if err != nil {
+			Log.Fatalf("Error while Fetching Image '%s': %s", imageName, err.Error())
+			memguard.SafeExit(1)
+		}
+
+		tag, err := img.FindTag(tagName, digest)
+		if err != nil {
+			Log.Error(err.Error())
+			memguard.SafeExit(1)
+		}
+
+		// the Deletion has to be confirmed if the Registry deletes other Tags, too,
+		// so the Objects are printed before the User is asked
+		shared := img.Aliases(tag)
+		confirmRequired := len(shared) > 0 && !imageDeleteYes
+
+		printPlan := func(format string, a ...interface{}) {
+			if confirmRequired && !imageDeleteDryRun {
+				fmt.Printf("Would delete " + format, a...)
+			} else {
+				printDeletion(imageDeleteDryRun, format, a...)
+			}
+		}
+
+		printPlan("%s:%s (%s) Manifest\n", img.Name, tag.Name, tag.ContentDigest)
+		for _, p := range tag.Platforms {
+			if len(p.Signatures) > 0 {
+				printPlan("%s:%s [%s] (%s) %d Signature(s)\n", img.Name, tag.Name, p.Platform, p.Digest, len(p.Signatures))
+			}
+		}
+		if tag.SignatureCount() > 0 {
+			printPlan("%s:%s (%s) %d Signature(s)\n", img.Name, tag.Name, tag.ContentDigest, tag.SignatureCount())
+		}
+
+		if len(shared) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: %s is also referenced by the Tags %s, they are deleted, too!\n", tag.ContentDigest, joinTagNames(shared))
+		}
+
+		if imageDeleteDryRun { return }
+
+		if confirmRequired && !confirm(fmt.Sprintf("Delete the Manifest of all %d Tags?", len(shared) + 1)) {
+			fmt.Printf("Nothing deleted (use --yes to confirm without a Prompt).\n")
+			memguard.SafeExit(1)
+		}
+
+		err = img.DeleteManifest(ctx, tag)
+		if err != nil {
+			Log.Errorf("Error while deleting Manifest of '%s': %s", args[0], err.Error())
+			memguard.SafeExit(1)
+		}
+	},
+}
+
+func init() {
+	imageCmd.AddCommand(imageDeleteCmd)
+
+	imageDeleteCmd.Flags().BoolVar(&imageDeleteDryRun, "dry-run", false, "Only print what would be deleted")
+	imageDeleteCmd.Flags().BoolVarP(&imageDeleteYes, "yes", "y", false, "Do not ask before deleting a Manifest which is shared by more than one Tag")
+}
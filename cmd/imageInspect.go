@@ -0,0 +1,148 @@
+/*
+Copyright © 2019 Emanuel Bennici <eb@fabmation.de>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/awnumar/memguard"
+	"github.com/spf13/cobra"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/output"
+	"github.com/fabmation-gmbh/oima/pkg/registry"
+	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
+)
+
+var inspectOutput string		// Output Format (table, json, yaml, csv)
+
+// inspectCmd represents the inspect command
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <image>:<tag>",
+	Short: "Show the Manifest and Image Configuration of an Image Tag",
+	Long: `Downloads the Manifest and the Image Configuration of an Image Tag
+and shows the Creation Time, Architecture/ OS, the compressed Size,
+all Layers and the Labels of the Image.
+
+If the Tag points to an Image Index (Multi-Arch Image), every
+Platform of the Index is shown.`,
+	Args: cobra.ExactArgs(1),
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		format, err := output.ParseFormat(inspectOutput)
+		if err != nil {
+			Log.Error(err.Error())
+			memguard.SafeExit(1)
+		}
+
+		imageName, tagName, digest, err := registry.ParseReference(args[0])
+		if err != nil {
+			Log.Error(err.Error())
+			memguard.SafeExit(1)
+		}
+
+		var dockerRegistry registry.DockerRegistry
+		dockerRegistry.Name = registryName
+		err = dockerRegistry.Init(ctx)
+		if err != nil {
+			Log.Fatalf("Error while Initialize DockerRegistry: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+
+		img, err := dockerRegistry.GetImage(ctx, imageName)
+		if err != nil {
+			Log.Fatalf("Error while Fetching Image '%s': %s", imageName, err.Error())
+			memguard.SafeExit(1)
+		}
+
+		tag, err := img.FindTag(tagName, digest)
+		if err != nil {
+			Log.Error(err.Error())
+			memguard.SafeExit(1)
+		}
+
+		manifests, err := img.InspectTag(ctx, tag)
+		if err != nil {
+			Log.Fatalf("Error while inspecting '%s:%s': %s", img.Name, tag.Name, err.Error())
+			memguard.SafeExit(1)
+		}
+
+		// the Labels and Layers do not fit into a Table, so show every Manifest as a Block
+		if format == output.Table {
+			fmt.Printf("\n>>>>> %s:%s (%s) <<<<<\n\n", img.Name, tag.Name, tag.ContentDigest)
+			if aliases := img.Aliases(tag); len(aliases) > 0 { fmt.Printf("Aliases: %s\n\n", joinTagNames(aliases)) }
+
+			for _, m := range manifests { printManifest(m) }
+			return
+		}
+
+		var records []output.Record
+		for _, m := range manifests {
+			records = append(records, output.NewManifestRecord(&dockerRegistry, img.Name, *tag, m))
+		}
+
+		err = output.Write(os.Stdout, format, output.ManifestColumns, records)
+		if err != nil {
+			Log.Errorf("Error while writing Output: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+	},
+}
+
+// printManifest prints the Informations of an inspected Manifest
+func printManifest(m rt.ManifestInfo) {
+	created := "-"
+	if !m.Created.IsZero() { created = m.Created.String() }
+
+	fmt.Printf("%s\n", m.Platform)
+	fmt.Printf(
+		"  Digest:           %s\n" +
+		"  Media Type:       %s\n" +
+		"  Created:          %s\n" +
+		"  Architecture/OS:  %s/%s\n" +
+		"  Size:             %s (%d Bytes)\n",
+		m.Digest, m.MediaType, created, m.Architecture, m.OS, rt.FormatSize(m.Size), m.Size)
+
+	fmt.Printf("  Layers:\n")
+	for _, l := range m.Layers { fmt.Printf("    %s  %10s\n", l.Digest, rt.FormatSize(l.Size)) }
+
+	fmt.Printf("  Labels:\n")
+	if len(m.Labels) == 0 { fmt.Printf("    -\n") }
+
+	keys := make([]string, 0, len(m.Labels))
+	for k := range m.Labels { keys = append(keys, k) }
+	sort.Strings(keys)
+
+	for _, k := range keys { fmt.Printf("    %s=%s\n", k, m.Labels[k]) }
+	fmt.Printf("\n")
+}
+
+func init() {
+	imageCmd.AddCommand(inspectCmd)
+
+	inspectCmd.Flags().StringVarP(&inspectOutput, "output", "o", string(output.Table), "Output Format (table, json, yaml, csv)")
+}
@@ -23,74 +23,83 @@ package cmd
 
 import (
 	"github.com/awnumar/memguard"
-	"github.com/fabmation-gmbh/oima/pkg/s3"
 	"github.com/spf13/cobra"
 	"os"
 
 	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/output"
 	"github.com/fabmation-gmbh/oima/pkg/registry"
 )
 
+var listOutput string		// Output Format (table, json, yaml, csv)
+var listFilter registry.Filter	// Selects the Repositories, Images and Tags
+var listSigned bool			// Only list signed Tags
+var listUnsigned bool		// Only list unsigned Tags
+var listLimit int			// Maximum Number of listed Tags (0 = unlimited)
+
 // listCmd represents the list command
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all Images of the Remote Registry",
+	Long: `Lists every Tag of all Images in the Registry with the
+Repository, Image, Tag, Digest, Number of Signatures and the
+Signature Backends (sigstore, notary) the Tag is signed in.
+
+Only the Repositories and Images matching the Filters are fetched
+from the Registry. '--repository' and '--image' are Glob Patterns
+(or Regular Expressions with '--regex'), '--tag' is a Regular Expression.
+
+Example:
+  oima image list --image 'team/nginx*' --tag '^v1\.' --unsigned`,
 
 	Run: func(cmd *cobra.Command, args []string) {
-		// ATTENTION: This is only for testing/ debugging!
-		var dockerRegistry registry.DockerRegistry
-		err := dockerRegistry.Init()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		format, err := output.ParseFormat(listOutput)
 		if err != nil {
-			Log.Panicf("Error while Initialize DockerRegistry: %s", err.Error())
+			Log.Error(err.Error())
+			memguard.SafeExit(1)
 		}
 
-		// =====] S3 Implementation Test [=====
-		var minioS3 s3.S3
-		minioS3 = &s3.S3Minio{}
+		if listSigned && listUnsigned {
+			Log.Error("The Flags '--signed' and '--unsigned' can not be combined")
+			memguard.SafeExit(1)
+		}
+
+		if listSigned { listFilter.SignState = registry.SSSigned }
+		if listUnsigned { listFilter.SignState = registry.SSUnsigned }
+
+		// no further Images are fetched once enough Tags are found,
+		// the Result contains exactly the first '--limit' Tags
+		listFilter.Limit = listLimit
 
-		err = minioS3.InitS3()
+		err = listFilter.Compile()
 		if err != nil {
-			Log.Fatalf("Error while Initializing S3 Struct: %s", err.Error())
+			Log.Error(err.Error())
 			memguard.SafeExit(1)
-		} else {
-			Log.Notice("S3 Struct initialized successfully")
 		}
 
-		// list Repos
-		repos := dockerRegistry.ListRepositories()
-		Log.Debugf("Returned %d Repositories", len(repos))
+		var dockerRegistry registry.DockerRegistry
+		dockerRegistry.Name = registryName
+		err = dockerRegistry.Init(ctx)
+		if err != nil {
+			Log.Errorf("Error while Initialize DockerRegistry: %s", err.Error())
+			memguard.SafeExit(1)
+		}
 
-		err = dockerRegistry.FetchAll()
+		err = dockerRegistry.FetchFiltered(ctx, &listFilter)
 		if err != nil {
-			Log.Fatalf("Error while Fetching All Informations from Registry '%s': %s", dockerRegistry.URI, err.Error())
-			os.Exit(1)
+			Log.Errorf("Error while Fetching Images from Registry '%s': %s", dockerRegistry.URI, err.Error())
+			memguard.SafeExit(1)
 		}
 
-		for _, v := range dockerRegistry.ListRepositories() {
-			Log.Noticef(">>>>> Repository: %s (%d Images) <<<<<", v.Name, len(v.Images))
-			image, _ := v.ListImages()
-
-			for _, img := range image {
-				Log.Debugf(">> Image: %s", img.Name)
-				tags, err := img.ListImageTags()
-
-				if err != nil {
-					Log.Errorf("Error while getting Image Tags of Image '%s': %s", img.Name, err.Error())
-					os.Exit(1)
-				}
-
-				//// check S3 Signature
-				//// list Objects
-				//var _img rt.BaseImage = &img
-				//err = minioS3.FetchSignatures(&_img)
-				//if err != nil {
-				//	Log.Errorf("Error while checking Signature of Image: %s", err.Error())
-				//	memguard.SafeExit(1)
-				//}
-
-				for _, tag := range tags { Log.Infof("  -- Tag: %s || Signature Found: %t || Content Digest: %s",
-																tag.Name, tag.S3SignFound, tag.ContentDigest) }
-			}
+		records := output.ImageRecords(&dockerRegistry)
+
+		err = output.Write(os.Stdout, format, output.ImageColumns, records)
+		if err != nil {
+			Log.Errorf("Error while writing Output: %s", err.Error())
+			memguard.SafeExit(1)
 		}
 	},
 }
@@ -98,13 +107,13 @@ var listCmd = &cobra.Command{
 func init() {
 	imageCmd.AddCommand(listCmd)
 
-	// Here you will define your flags and configuration settings.
-
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// listCmd.PersistentFlags().String("foo", "", "A help for foo")
-
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// listCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	listCmd.Flags().StringVarP(&listOutput, "output", "o", string(output.Table), "Output Format (table, json, yaml, csv)")
+	listCmd.Flags().StringVar(&listFilter.Repository, "repository", "", "Only list Images in Repositories matching the Pattern")
+	listCmd.Flags().StringVar(&listFilter.Image, "image", "", "Only list Images (full Name) matching the Pattern")
+	listCmd.Flags().BoolVar(&listFilter.Regex, "regex", false, "Treat '--repository' and '--image' as Regular Expressions instead of Globs")
+	listCmd.Flags().StringVar(&listFilter.Tag, "tag", "", "Only list Tags matching the Regular Expression")
+	listCmd.Flags().StringVar(&listFilter.Digest, "digest", "", "Only list Tags whose Digest starts with the Prefix (eg. 'sha256:ab12')")
+	listCmd.Flags().BoolVar(&listSigned, "signed", false, "Only list signed Tags")
+	listCmd.Flags().BoolVar(&listUnsigned, "unsigned", false, "Only list unsigned Tags")
+	listCmd.Flags().IntVar(&listLimit, "limit", 0, "Maximum Number of listed Tags (0 = unlimited)")
 }
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestListJSONOutput runs 'image list -o json' (and 'registry stats -o json') in a
+// Sub-Process and checks that Stdout only contains the JSON Document, the Log
+// Messages (eg. 'Signature Store initialized') must be written to Stderr
+func TestListJSONOutput(t *testing.T) {
+	if args := os.Getenv("OIMA_TEST_ARGS"); len(args) > 0 {
+		rootCmd.SetArgs(strings.Split(args, " "))
+		Execute()
+		return
+	}
+
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/":
+			w.WriteHeader(200)
+		case r.URL.Path == "/v2/_catalog":
+			fmt.Fprint(w, `{"repositories":["team/app"]}`)
+		case r.URL.Path == "/v2/team/app/tags/list":
+			fmt.Fprint(w, `{"tags":["1.0","latest"]}`)
+		case strings.HasPrefix(r.URL.Path, "/v2/team/app/manifests/"):
+			w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+			w.Header().Set("Docker-Content-Digest", "sha256:aaaa")
+			fmt.Fprint(w, `{"schemaVersion":2,"mediaType":"application/vnd.docker.distribution.manifest.v2+json"}`)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer registry.Close()
+
+	dir, err := ioutil.TempDir("", "oima-list")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+
+	if err := os.Mkdir(filepath.Join(dir, "sigstore"), 0700); err != nil { t.Fatal(err) }
+
+	config := filepath.Join(dir, "oima.yaml")
+	err = ioutil.WriteFile(config, []byte(fmt.Sprintf(
+		"registry:\n  uri: %q\n  sigstore: %q\ncredential:\n  vault: %q\n",
+		registry.URL, "file://" + filepath.Join(dir, "sigstore"), filepath.Join(dir, "oima.vault"))), 0600)
+	if err != nil { t.Fatal(err) }
+
+	for _, args := range []string{"image list -o json", "registry stats -o json"} {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestListJSONOutput$")
+		cmd.Env = append(os.Environ(), "OIMA_TEST_ARGS=" + args + " --config " + config)
+
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+		if err := cmd.Run(); err != nil { t.Fatalf("'%s' failed: %s\n%s", args, err, stderr.String()) }
+
+		var records []map[string]interface{}
+		if err := json.Unmarshal(stdout.Bytes(), &records); err != nil {
+			t.Fatalf("Stdout of '%s' is not valid JSON: %s\n%s", args, err, stdout.String())
+		}
+
+		if len(records) == 0 { t.Errorf("'%s' returned no Records", args) }
+	}
+}
@@ -16,9 +16,12 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"github.com/fabmation-gmbh/oima/pkg/s3"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 
 	"github.com/apsdehal/go-logger"
 	"github.com/awnumar/memguard"
@@ -29,13 +32,15 @@ import (
 	. "github.com/fabmation-gmbh/oima/internal/log"
 	"github.com/fabmation-gmbh/oima/pkg/config"
 	"github.com/fabmation-gmbh/oima/pkg/credential"
+	"github.com/fabmation-gmbh/oima/pkg/http"
 	"github.com/fabmation-gmbh/oima/pkg/ui"
 )
 
 
 var (
-	cfgFile string		// Application Config File
-	debug	bool		// Print Debug Messages
+	cfgFile			string		// Application Config File
+	debug			bool		// Print Debug Messages
+	registryName	string		// Name of the selected Registry (empty selects the first Registry)
 )
 
 var Config config.Configuration
@@ -56,7 +61,16 @@ manually delete the Directory/ Signature.
 This Tool automates this Process and helps to keep
 track of all signed Images.`,
 	Version: internal.GetVersion(),
-	Run: func(cmd *cobra.Command, args []string) { ui.StartUI()	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		err := ui.StartUI(ctx, registryName)
+		if err != nil {
+			Log.Error(err.Error())
+			memguard.SafeExit(1)
+		}
+	},
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		// initialize CredStore struct
 		internal.Cred = new(credential.CredStore)
@@ -68,38 +82,100 @@ track of all signed Images.`,
 
 		Config = internal.GetConfig()
 
-		// map Credentials into CredStore
-		if len(Config.Registry.Password) > 0 {
-			// map User Password into CredStore
-			err := internal.Cred.AddCredential("password", []byte(Config.Registry.Password))
-			if err != nil {
-				Log.Fatal(err.Error())
-			}
+		// configure the Retry Policy of all Requests
+		if err := http.SetRetryPolicy(Config.Retry); err != nil {
+			Log.Error(err.Error())
+			memguard.SafeExit(1)
 		}
 
-		// map S3 Credentials into CredStore
+		// map Registry Passwords into CredStore
+		for _, reg := range Config.GetRegistries() {
+			mapCredential(config.CredentialName("password", reg.Name), reg.Password)
+		}
+
+		// map S3 Credentials (global 's3' Block and named Signature Stores) into CredStore
 		if Config.S3.Enabled {
-			// copy S3 access Key ID into CredStore
-			err := internal.Cred.AddCredential("s3_accessKeyID", []byte(Config.S3.AccessKeyID))
+			mapCredential("s3_accessKeyID", Config.S3.AccessKeyID)
+			mapCredential("s3_secretAccessKeyID", Config.S3.SecretAccessKey)
+		} else {
+			Log.Debugf("The S3 Component of this CLI was disabled by User Configuration.")
+		}
+
+		for _, store := range Config.Sigstores {
+			if store.URI != "s3" { continue }
+
+			mapCredential(config.CredentialName("s3_accessKeyID", store.Name), store.S3.AccessKeyID)
+			mapCredential(config.CredentialName("s3_secretAccessKeyID", store.Name), store.S3.SecretAccessKey)
+		}
+
+		// map Notary Credentials into CredStore
+		if Config.Notary.Enabled && len(Config.Notary.Password) > 0 {
+			err := internal.Cred.AddCredential("notary_password", []byte(Config.Notary.Password))
 			if err != nil { Log.Fatal(err.Error()); memguard.SafeExit(1) }
+		}
 
-			// copy S3 Secret access Key into CredStore
-			err = internal.Cred.AddCredential("s3_secretAccessKeyID", []byte(Config.S3.SecretAccessKey))
+		// load the Credentials which are not set in the Configuration File from the Vault
+		if credential.VaultExists(Config.VaultPath()) {
+			passphrase, err := readSecret("OIMA_VAULT_PASSPHRASE", "Vault Passphrase: ")
 			if err != nil { Log.Fatal(err.Error()); memguard.SafeExit(1) }
 
-			// initialize S3
-			S3Data := &s3.S3Minio{}
-			err = S3Data.InitS3()
+			err = internal.Cred.LoadVault(Config.VaultPath(), passphrase)
+			memguard.WipeBytes(passphrase)
 			if err != nil {
-				Log.Fatalf("Error while initializing MinIO S3 Client: %s", err.Error())
+				Log.Fatalf("Error while opening Credential Vault '%s': %s", Config.VaultPath(), err.Error())
 				memguard.SafeExit(1)
 			}
-		} else {
-			Log.Debugf("The S3 Component of this CLI was disabled by User Configuration.")
+		}
+
+		// no Password configured (Configuration File or Vault), so use the Credentials of 'docker login'
+		for _, reg := range Config.GetRegistries() {
+			if len(registryName) > 0 && reg.Name != registryName { continue }
+			if auth, _ := strconv.ParseBool(reg.RequireAuth); !auth { continue }
+
+			name := config.CredentialName("password", reg.Name)
+			if _, err := internal.Cred.GetCredential(name); err == nil { continue }
+
+			username, err := internal.Cred.AddDockerCredential(name, reg.RegistryURI, reg.DockerConfig)
+			if err != nil {
+				Log.Fatalf("No Password configured for Registry '%s' and unable to get the Docker Credentials: %s", reg.Name, err.Error())
+				memguard.SafeExit(1)
+			}
+
+			if len(reg.Username) == 0 { mapCredential(config.CredentialName("username", reg.Name), username) }
 		}
 	},
 }
 
+// mapCredential adds @value (if set) under @name to the CredStore
+func mapCredential(name string, value string) {
+	if len(value) == 0 { return }
+
+	err := internal.Cred.AddCredential(name, []byte(value))
+	if err != nil { Log.Fatal(err.Error()); memguard.SafeExit(1) }
+}
+
+// commandContext returns the Context for the Requests of a Command,
+// it is canceled on SIGINT/ SIGTERM (or by calling the CancelFunc)
+func commandContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-signals:
+			Log.Warning("Interrupted, canceling all Requests")
+			cancel()
+		case <-ctx.Done():
+		}
+
+		signal.Stop(signals)
+	}()
+
+	return ctx, cancel
+}
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		Log.Error(err.Error())
@@ -114,6 +190,7 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.oima.yaml)")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Print Debug Messages (defaults to false)")
+	rootCmd.PersistentFlags().StringVar(&registryName, "registry", "", "Name of the Registry in the 'registries' List (defaults to the first Registry)")
 
 	//rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
@@ -137,9 +214,9 @@ func initConfig() {
 	}
 
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	} else {
-		fmt.Printf("No Config File found! Maybe run '%s configure' first\n", applicationName)
+		fmt.Fprintf(os.Stderr, "No Config File found! Maybe run '%s configure' first\n", applicationName)
 		memguard.SafeExit(1)
 	}
 }
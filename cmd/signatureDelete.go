@@ -0,0 +1,256 @@
+/*
+Copyright © 2019 Emanuel Bennici <eb@fabmation.de>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/awnumar/memguard"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/registry"
+	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
+	"github.com/fabmation-gmbh/oima/pkg/sigstore"
+)
+
+var (
+	deleteAllTags	bool		// Delete the Signatures of all Tags of the Image
+	deleteDryRun	bool		// Only print what would be deleted
+	deleteSigName	string		// Delete only this Signature (eg. 'signature-2')
+	deleteYes		bool		// Do not ask before deleting the Signatures of more than one Tag
+)
+
+// signatureDeleteCmd represents the signature delete command
+var signatureDeleteCmd = &cobra.Command{
+	Use:   "delete <image>:<tag> | <image>@sha256:<digest>",
+	Short: "Delete the Signatures of an Image Tag",
+	Long: `Resolves the Digest of an Image Tag and deletes its Signatures
+from the Signature Store and its Trust Data from the Notary Server.
+
+Signatures belong to the Digest, so they are deleted for all Tags pointing
+to it (eg. deleting the Signatures of 'latest' also unsigns '1.4.2').
+If more than one Tag is affected, the Deletion has to be confirmed
+(or '--yes' has to be set).
+
+Consumers (eg. containers/image) stop at the first missing 'signature-N',
+so if a single Signature is deleted with '--signature', the following
+Signatures are renumbered (eg. 'signature-3' becomes 'signature-2').
+
+The Command exits with a non-zero Exit Code if no Signature was found.`,
+	Args: cobra.ExactArgs(1),
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		imageName, tagName, digest, err := registry.ParseReference(args[0])
+		if err != nil {
+			Log.Error(err.Error())
+			memguard.SafeExit(1)
+		}
+
+		var dockerRegistry registry.DockerRegistry
+		dockerRegistry.Name = registryName
+		err = dockerRegistry.Init(ctx)
+		if err != nil {
+			Log.Fatalf("Error while Initialize DockerRegistry: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+
+		img, err := dockerRegistry.GetImage(ctx, imageName)
+		if err != nil {
+			Log.Fatalf("Error while Fetching Image '%s': %s", imageName, err.Error())
+			memguard.SafeExit(1)
+		}
+
+		// collect Tags
+		var tags []*rt.Tag
+		if deleteAllTags {
+			for iTag := range img.Tags { tags = append(tags, &img.Tags[iTag]) }
+		} else {
+			tag, err := img.FindTag(tagName, digest)
+			if err != nil {
+				Log.Error(err.Error())
+				memguard.SafeExit(1)
+			}
+
+			tags = append(tags, tag)
+		}
+
+		signature := rt.AllSignatures
+		if len(deleteSigName) > 0 {
+			if !sigstore.IsSignatureName(deleteSigName) {
+				Log.Errorf("Invalid Signature Name '%s' (expected 'signature-N')", deleteSigName)
+				memguard.SafeExit(1)
+			}
+
+			signature = deleteSigName
+		}
+
+		// Signatures belongs to a Digest, so every Digest is only processed
+		// once and all Tags of the Digest (the Aliases) are affected
+		type deletion struct {
+			tag			*rt.Tag
+			signatures	[]string		// Signatures of the Digest
+			renumbered	[]string		// Signatures which are moved down (see sigstore.DeleteSignature())
+			platforms	bool			// Delete the Signatures of the Platform Manifests
+			trustData	bool			// Delete the Trust Data
+		}
+
+		var found int
+		var deletions []deletion
+		processed := make(map[string]bool)
+		affected := make(map[string]bool)
+
+		for _, tag := range tags {
+			if processed[tag.ContentDigest] { continue }
+			processed[tag.ContentDigest] = true
+
+			signatures := tag.Signatures
+			var renumbered []string
+			if signature != rt.AllSignatures {
+				signatures = nil
+				for _, v := range tag.Signatures {
+					if v == signature {
+						signatures = append(signatures, v)
+					} else if len(signatures) > 0 {
+						renumbered = append(renumbered, v)
+					}
+				}
+			}
+
+			// all Signatures include the Signatures of the Platform Manifests
+			var platformSignatures int
+			if signature == rt.AllSignatures {
+				for _, p := range tag.Platforms { platformSignatures += len(p.Signatures) }
+			}
+
+			trustData := tag.NotarySignFound && signature == rt.AllSignatures
+			if len(signatures) == 0 && platformSignatures == 0 && !trustData { continue }
+
+			found += len(signatures) + platformSignatures
+			if trustData { found++ }
+
+			deletions = append(deletions, deletion{ tag: tag, signatures: signatures, renumbered: renumbered, platforms: platformSignatures > 0, trustData: trustData })
+			affected[tag.ContentDigest] = true
+		}
+
+		if found == 0 {
+			fmt.Printf("No Signatures found.\n")
+			memguard.SafeExit(1)
+		}
+
+		var affectedTags []string
+		for _, v := range img.Tags {
+			if affected[v.ContentDigest] { affectedTags = append(affectedTags, string(v.Name)) }
+		}
+
+		// the Deletion has to be confirmed if more than one Tag is affected,
+		// so the Signatures are printed before the User is asked
+		confirmRequired := len(affectedTags) > 1 && !deleteYes
+
+		printPlan := func(format string, a ...interface{}) {
+			if confirmRequired && !deleteDryRun {
+				fmt.Printf("Would delete " + format, a...)
+			} else {
+				printDeletion(deleteDryRun, format, a...)
+			}
+		}
+
+		for _, d := range deletions {
+			for _, name := range d.signatures {
+				printPlan("%s:%s (%s) %s\n", img.Name, d.tag.Name, d.tag.ContentDigest, name)
+			}
+			for iName, name := range d.renumbered {
+				previous := d.signatures[0]
+				if iName > 0 { previous = d.renumbered[iName - 1] }
+
+				fmt.Printf("%s:%s (%s) %s is renumbered to %s\n", img.Name, d.tag.Name, d.tag.ContentDigest, name, previous)
+			}
+			if d.platforms {
+				for _, p := range d.tag.Platforms {
+					for _, name := range p.Signatures {
+						printPlan("%s:%s [%s] (%s) %s\n", img.Name, d.tag.Name, p.Platform, p.Digest, name)
+					}
+				}
+			}
+			if d.trustData {
+				printPlan("%s:%s (%s) Notary Trust Data\n", img.Name, d.tag.Name, d.tag.ContentDigest)
+			}
+		}
+
+		if len(affectedTags) > 1 {
+			fmt.Printf("The Signatures are shared by %d Tags: %s\n", len(affectedTags), strings.Join(affectedTags, ", "))
+		}
+
+		if deleteDryRun { return }
+
+		if confirmRequired && !confirm(fmt.Sprintf("Delete the Signatures of all %d Tags?", len(affectedTags))) {
+			fmt.Printf("Nothing deleted (use --yes to confirm without a Prompt).\n")
+			memguard.SafeExit(1)
+		}
+
+		for _, d := range deletions {
+			err = img.DeleteSignature(ctx, d.tag, signature)
+			if err != nil {
+				Log.Errorf("Error while deleting Signatures of '%s:%s': %s", img.Name, d.tag.Name, err.Error())
+				memguard.SafeExit(1)
+			}
+		}
+	},
+}
+
+// printDeletion prints a deleted (or with @dryRun to be deleted) Object
+func printDeletion(dryRun bool, format string, a ...interface{}) {
+	if dryRun {
+		fmt.Printf("[dry-run] Would delete " + format, a...)
+	} else {
+		fmt.Printf("Deleting " + format, a...)
+	}
+}
+
+// confirm asks the User to confirm @question, only 'y' and 'yes' are accepted.
+// Nothing is confirmed if Stdin is not a Terminal (Scripts have to use '--yes').
+func confirm(question string) bool {
+	if !terminal.IsTerminal(int(syscall.Stdin)) { return false }
+
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
+
+func init() {
+	signatureCmd.AddCommand(signatureDeleteCmd)
+
+	signatureDeleteCmd.Flags().BoolVar(&deleteAllTags, "all-tags", false, "Delete the Signatures of all Tags of the Image")
+	signatureDeleteCmd.Flags().BoolVar(&deleteDryRun, "dry-run", false, "Only print which Signatures would be deleted")
+	signatureDeleteCmd.Flags().StringVar(&deleteSigName, "signature", "", "Delete only this Signature (eg. 'signature-2')")
+	signatureDeleteCmd.Flags().BoolVarP(&deleteYes, "yes", "y", false, "Do not ask before deleting Signatures which are shared by more than one Tag")
+}
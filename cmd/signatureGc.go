@@ -0,0 +1,107 @@
+/*
+Copyright © 2019 Emanuel Bennici <eb@fabmation.de>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/awnumar/memguard"
+	"github.com/spf13/cobra"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/registry"
+	"github.com/fabmation-gmbh/oima/pkg/sigstore"
+)
+
+var gcApply bool		// Delete the orphaned Signatures
+
+// gcCmd represents the gc command
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Find and delete orphaned Signatures",
+	Long: `Lists all '<registry>/<image>@<algo>=<digest>/' Prefixes in the
+Signature Store and reports the Signatures whose Digest does not
+exist in the Registry anymore (eg. because the Manifest was deleted).
+Digests which are not referenced by any Tag, but are still present
+in the Registry (eg. pulled by Digest), are kept.
+
+The orphaned Signatures are only reported, use --apply to delete them.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		var dockerRegistry registry.DockerRegistry
+		dockerRegistry.Name = registryName
+		err := dockerRegistry.Init(ctx)
+		if err != nil {
+			Log.Fatalf("Error while Initialize DockerRegistry: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+
+		err = dockerRegistry.FetchAll(ctx)
+		if err != nil {
+			Log.Fatalf("Error while Fetching All Informations from Registry '%s': %s", dockerRegistry.URI, err.Error())
+			memguard.SafeExit(1)
+		}
+
+		orphans, err := dockerRegistry.FindOrphanedSignatures(ctx)
+		if err != nil {
+			Log.Fatalf("Error while searching orphaned Signatures: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+
+		var count int
+		for _, ref := range orphans {
+			signatures, err := dockerRegistry.ListDigestSignatures(ctx, ref)
+			if err != nil {
+				Log.Fatalf("Error while listing Signatures of '%s': %s", sigstore.DigestPath(ref.Image, ref.Digest), err.Error())
+				memguard.SafeExit(1)
+			}
+
+			for _, name := range signatures {
+				fmt.Printf("%s\n", sigstore.SignaturePath(ref.Image, ref.Digest, name))
+			}
+			count += len(signatures)
+
+			if gcApply {
+				err = dockerRegistry.DeleteDigestSignatures(ctx, ref)
+				if err != nil {
+					Log.Fatalf("Error while deleting Signatures of '%s': %s", sigstore.DigestPath(ref.Image, ref.Digest), err.Error())
+					memguard.SafeExit(1)
+				}
+			}
+		}
+
+		if gcApply {
+			fmt.Printf("\nDeleted %d orphaned Signatures of %d Digests.\n", count, len(orphans))
+		} else {
+			fmt.Printf("\nFound %d orphaned Signatures of %d Digests, run with --apply to delete them.\n", count, len(orphans))
+		}
+	},
+}
+
+func init() {
+	signatureCmd.AddCommand(gcCmd)
+
+	gcCmd.Flags().BoolVar(&gcApply, "apply", false, "Delete the orphaned Signatures")
+}
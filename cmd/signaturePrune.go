@@ -0,0 +1,125 @@
+/*
+Copyright © 2019 Emanuel Bennici <eb@fabmation.de>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/awnumar/memguard"
+	"github.com/spf13/cobra"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/prune"
+	"github.com/fabmation-gmbh/oima/pkg/registry"
+	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
+)
+
+var pruneApply bool		// Execute the Plan
+
+// pruneCmd represents the prune command
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete Signatures based on the Retention Policy",
+	Long: `Walks through all Images of the Registry and decides, based on
+the Retention Policy ('prune' Block in the Configuration), which
+Signatures are not needed anymore:
+
+  keepLast   Keep the Signatures of the newest N signed Tags of every Image
+  keepTags   Keep the Signatures of Tags matching one of the Regular Expressions
+  maxAge     Drop the Signatures of Tags signed before this Duration
+
+Signatures whose signed Digest does not match the Digest they are stored
+for are always dropped (the following Signatures are renumbered).
+Signatures belong to a Digest, so they are planned once for all Tags
+pointing to it and are only deleted if the Policy drops all of them.
+The Plan is only printed, use --apply to delete the Signatures.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		policy, err := prune.NewPolicy(Config.Prune)
+		if err != nil {
+			Log.Fatalf("Invalid Retention Policy: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+
+		var dockerRegistry registry.DockerRegistry
+		dockerRegistry.Name = registryName
+		err = dockerRegistry.Init(ctx)
+		if err != nil {
+			Log.Fatalf("Error while Initialize DockerRegistry: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+
+		err = dockerRegistry.FetchAll(ctx)
+		if err != nil {
+			Log.Fatalf("Error while Fetching All Informations from Registry '%s': %s", dockerRegistry.URI, err.Error())
+			memguard.SafeExit(1)
+		}
+
+		actions, err := policy.Plan(ctx, &dockerRegistry)
+		if err != nil {
+			Log.Fatalf("Error while creating Prune Plan: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+
+		var toDelete int
+		for _, v := range actions {
+			decision := "KEEP"
+			if v.Delete {
+				decision = "DELETE"
+				toDelete++
+			}
+
+			signature := v.Signature
+			if signature == rt.AllSignatures { signature = "*" }
+
+			// the Decision applies to all Tags of the Digest
+			tags := string(v.Tag.Name)
+			if aliases := v.Image.Aliases(v.Tag); len(aliases) > 0 { tags += ", " + joinTagNames(aliases) }
+
+			fmt.Printf("%-7s %s:%s (%s) %s => %s\n", decision, v.Image.Name, tags, v.Tag.ContentDigest,
+				signature, v.Reason)
+		}
+
+		fmt.Printf("\n%d of %d Entries would be deleted.\n", toDelete, len(actions))
+
+		if !pruneApply {
+			fmt.Printf("Nothing deleted, run with --apply to execute the Plan.\n")
+			return
+		}
+
+		err = prune.Apply(ctx, actions)
+		if err != nil {
+			Log.Errorf("Error while executing Prune Plan: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+		fmt.Printf("Plan executed.\n")
+	},
+}
+
+func init() {
+	signatureCmd.AddCommand(pruneCmd)
+
+	pruneCmd.Flags().BoolVar(&pruneApply, "apply", false, "Execute the Plan and delete the Signatures")
+}
@@ -0,0 +1,144 @@
+/*
+Copyright © 2019 Emanuel Bennici <eb@fabmation.de>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/awnumar/memguard"
+	"github.com/spf13/cobra"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/registry"
+	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
+	"github.com/fabmation-gmbh/oima/pkg/signature"
+)
+
+// showCmd represents the show command
+var showCmd = &cobra.Command{
+	Use:   "show <image>:<tag>",
+	Short: "Show the Signatures of an Image Tag",
+	Long: `Downloads all Signatures of an Image Tag and shows
+the signed Informations (Manifest Digest, Docker Reference,
+Creator, Timestamp and the ID of the signing Key).`,
+	Args: cobra.ExactArgs(1),
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		imageName, tagName, digest, err := registry.ParseReference(args[0])
+		if err != nil {
+			Log.Error(err.Error())
+			memguard.SafeExit(1)
+		}
+
+		var dockerRegistry registry.DockerRegistry
+		dockerRegistry.Name = registryName
+		err = dockerRegistry.Init(ctx)
+		if err != nil {
+			Log.Fatalf("Error while Initialize DockerRegistry: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+
+		img, err := dockerRegistry.GetImage(ctx, imageName)
+		if err != nil {
+			Log.Fatalf("Error while Fetching Image '%s': %s", imageName, err.Error())
+			memguard.SafeExit(1)
+		}
+
+		tag, err := img.FindTag(tagName, digest)
+		if err != nil {
+			Log.Error(err.Error())
+			memguard.SafeExit(1)
+		}
+
+		fmt.Printf("\n>>>>> Signatures of %s:%s (%s) <<<<<\n\n", img.Name, tag.Name, tag.ContentDigest)
+
+		// the Signatures belong to the Digest, so they are shared with all Aliases
+		if aliases := img.Aliases(tag); len(aliases) > 0 {
+			fmt.Printf("Aliases: %s\n\n", joinTagNames(aliases))
+		}
+
+		// list the Signatures of the Platform Manifests of an Image Index
+		if tag.IsIndex() {
+			fmt.Printf("Platforms:\n")
+			for _, p := range tag.Platforms {
+				signatures := "-"
+				if len(p.Signatures) > 0 { signatures = strings.Join(p.Signatures, ", ") }
+
+				fmt.Printf("  %-20s %s  %s\n", p.Platform, p.Digest, signatures)
+			}
+			fmt.Printf("\n")
+		}
+
+		if tag.SignatureCount() == 0 {
+			fmt.Printf("No Signatures found.\n")
+			return
+		}
+
+		for _, name := range tag.Signatures {
+			fmt.Printf("%s\n", name)
+
+			data, err := img.ReadSignature(ctx, tag, name)
+			if err != nil {
+				fmt.Printf("  Error while downloading Signature: %s\n\n", err.Error())
+				continue
+			}
+
+			sig, err := signature.Parse(data)
+			if err != nil {
+				fmt.Printf("  Error while parsing Signature: %s\n\n", err.Error())
+				continue
+			}
+
+			printSignature(sig)
+		}
+	},
+}
+
+// joinTagNames returns the comma separated List of the Tag Names @names
+func joinTagNames(names []rt.TagName) string {
+	var list []string
+	for _, v := range names { list = append(list, string(v)) }
+
+	return strings.Join(list, ", ")
+}
+
+// printSignature prints the Informations of a parsed Signature
+func printSignature(sig *signature.Signature) {
+	timestamp := "-"
+	if !sig.Timestamp.IsZero() { timestamp = sig.Timestamp.String() }
+
+	fmt.Printf(
+		"  Manifest Digest:  %s\n" +
+		"  Docker Reference: %s\n" +
+		"  Creator:          %s\n" +
+		"  Timestamp:        %s\n" +
+		"  Key ID:           %s\n\n",
+		sig.ManifestDigest, sig.DockerReference, sig.Creator, timestamp, sig.KeyID)
+}
+
+func init() {
+	signatureCmd.AddCommand(showCmd)
+}
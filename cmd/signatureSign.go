@@ -0,0 +1,186 @@
+/*
+Copyright © 2019 Emanuel Bennici <eb@fabmation.de>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/awnumar/memguard"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/openpgp"
+
+	"github.com/fabmation-gmbh/oima/internal"
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/errors"
+	"github.com/fabmation-gmbh/oima/pkg/registry"
+	"github.com/fabmation-gmbh/oima/pkg/signature"
+)
+
+var signKeyID string		// ID of the GPG Key used to sign the Image
+
+// signCmd represents the sign command
+var signCmd = &cobra.Command{
+	Use:   "sign <image>:<tag>",
+	Short: "Sign an Image Tag and upload the Signature",
+	Long: `Resolves the Digest of an Image Tag, creates a simple signing
+Payload, signs it with a GPG Secret Key of the configured Secret
+Keyring ('signature.secretKeyring') and uploads the Signature
+as next free 'signature-N' Object to the Signature Store.
+
+If the Key is encrypted, the Passphrase is read from the Environment
+Variable OIMA_GPG_PASSPHRASE or prompted interactively.`,
+	Args: cobra.ExactArgs(1),
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		imageName, tagName, digest, err := registry.ParseReference(args[0])
+		if err != nil {
+			Log.Error(err.Error())
+			memguard.SafeExit(1)
+		}
+
+		if len(Config.Signature.SecretKeyring) == 0 {
+			Log.Error("No Secret Keyring configured ('signature.secretKeyring'), unable to sign Images")
+			memguard.SafeExit(1)
+		}
+
+		keyring, err := signature.LoadSecretKeyring(Config.Signature.SecretKeyring)
+		if err != nil {
+			Log.Fatalf("Error while loading Secret Keyring: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+
+		signer, err := signature.FindKey(keyring, signKeyID)
+		if err != nil {
+			Log.Error(err.Error())
+			memguard.SafeExit(1)
+		}
+
+		var dockerRegistry registry.DockerRegistry
+		dockerRegistry.Name = registryName
+		err = dockerRegistry.Init(ctx)
+		if err != nil {
+			Log.Fatalf("Error while Initialize DockerRegistry: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+
+		img, err := dockerRegistry.GetImage(ctx, imageName)
+		if err != nil {
+			Log.Fatalf("Error while Fetching Image '%s': %s", imageName, err.Error())
+			memguard.SafeExit(1)
+		}
+
+		tag, err := img.FindTag(tagName, digest)
+		if err != nil {
+			Log.Error(err.Error())
+			memguard.SafeExit(1)
+		}
+
+		reference := img.GetReference(tag)
+		keyID := signature.FormatKeyID(signer.PrimaryKey.KeyId)
+
+		// refuse to sign if an identical Signature already exists
+		for _, name := range tag.Signatures {
+			data, err := img.ReadSignature(ctx, tag, name)
+			if err != nil {
+				Log.Fatalf("Error while downloading Signature '%s': %s", name, err.Error())
+				memguard.SafeExit(1)
+			}
+
+			sig, err := signature.Parse(data)
+			if err != nil {
+				Log.Warningf("Ignoring invalid Signature '%s': %s", name, err.Error())
+				continue
+			}
+
+			if isSameSignature(sig, signer, tag.ContentDigest, reference) {
+				Log.Error(errors.NewSignatureExistsError(name).Error())
+				memguard.SafeExit(1)
+			}
+		}
+
+		// decrypt private Key
+		if signature.IsEncrypted(signer) {
+			passphrase, err := readPassphrase(keyID)
+			if err != nil {
+				Log.Fatalf("Error while reading Passphrase: %s", err.Error())
+				memguard.SafeExit(1)
+			}
+
+			err = signature.DecryptKey(signer, passphrase)
+			memguard.WipeBytes(passphrase)
+			if err != nil {
+				Log.Fatalf("Error while decrypting Key %s: %s", keyID, err.Error())
+				memguard.SafeExit(1)
+			}
+		}
+
+		payload, err := signature.NewPayload(reference, tag.ContentDigest, fmt.Sprintf("oima %s", internal.Version))
+		if err != nil {
+			Log.Fatalf("Error while creating Payload: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+
+		data, err := signature.Sign(payload, signer)
+		if err != nil {
+			Log.Fatalf("Error while signing '%s': %s", reference, err.Error())
+			memguard.SafeExit(1)
+		}
+
+		name, err := img.WriteSignature(ctx, tag, data)
+		if err != nil {
+			Log.Fatalf("Error while uploading Signature: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+
+		fmt.Printf("Signed %s (%s) with Key %s => %s\n", reference, tag.ContentDigest, keyID, name)
+	},
+}
+
+// isSameSignature returns true if @sig was created by @signer
+// for the same Manifest Digest and Docker Reference
+func isSameSignature(sig *signature.Signature, signer *openpgp.Entity, digest string, reference string) bool {
+	if sig.ManifestDigest != digest || sig.DockerReference != reference { return false }
+
+	// the Signature could be created by a Sub-Key of @signer
+	if sig.KeyID == signature.FormatKeyID(signer.PrimaryKey.KeyId) { return true }
+	for _, v := range signer.Subkeys {
+		if sig.KeyID == signature.FormatKeyID(v.PublicKey.KeyId) { return true }
+	}
+
+	return false
+}
+
+// readPassphrase returns the Passphrase of the Key @keyID from
+// OIMA_GPG_PASSPHRASE or prompts the User for it
+func readPassphrase(keyID string) ([]byte, error) {
+	return readSecret("OIMA_GPG_PASSPHRASE", fmt.Sprintf("Enter Passphrase for Key %s: ", keyID))
+}
+
+func init() {
+	signatureCmd.AddCommand(signCmd)
+
+	signCmd.Flags().StringVar(&signKeyID, "key", "", "ID or Fingerprint of the GPG Key used to sign the Image")
+	_ = signCmd.MarkFlagRequired("key")
+}
@@ -0,0 +1,158 @@
+/*
+Copyright © 2019 Emanuel Bennici <eb@fabmation.de>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/awnumar/memguard"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/openpgp"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/registry"
+	"github.com/fabmation-gmbh/oima/pkg/signature"
+)
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify <image>:<tag>",
+	Short: "Verify the Signatures of an Image Tag",
+	Long: `Verifies all Signatures of an Image Tag against the configured
+Keyring ('signature.keyring') and checks if the signed Manifest Digest
+matches the current Digest of the Tag in the Registry. The signed Docker
+Reference must be the Tag (or one of its Aliases) in this Registry.
+
+If the Tag points to an Image Index, the Signatures of the Platform
+Manifests are verified, too (Platforms without Signatures are skipped).
+
+The Command exits with a non-zero Exit Code if not all Signatures are valid.`,
+	Args: cobra.ExactArgs(1),
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		imageName, tagName, digest, err := registry.ParseReference(args[0])
+		if err != nil {
+			Log.Error(err.Error())
+			memguard.SafeExit(1)
+		}
+
+		if len(Config.Signature.Keyring) == 0 {
+			Log.Error("No Keyring configured ('signature.keyring'), unable to verify Signatures")
+			memguard.SafeExit(1)
+		}
+
+		keyring, err := signature.LoadKeyring(Config.Signature.Keyring)
+		if err != nil {
+			Log.Fatalf("Error while loading Keyring: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+
+		var dockerRegistry registry.DockerRegistry
+		dockerRegistry.Name = registryName
+		err = dockerRegistry.Init(ctx)
+		if err != nil {
+			Log.Fatalf("Error while Initialize DockerRegistry: %s", err.Error())
+			memguard.SafeExit(1)
+		}
+
+		img, err := dockerRegistry.GetImage(ctx, imageName)
+		if err != nil {
+			Log.Fatalf("Error while Fetching Image '%s': %s", imageName, err.Error())
+			memguard.SafeExit(1)
+		}
+
+		tag, err := img.FindTag(tagName, digest)
+		if err != nil {
+			Log.Error(err.Error())
+			memguard.SafeExit(1)
+		}
+
+		fmt.Printf("\n>>>>> Verifying Signatures of %s:%s (%s) <<<<<\n\n", img.Name, tag.Name, tag.ContentDigest)
+
+		var total int
+		for _, p := range tag.Platforms { total += len(p.Signatures) }
+		total += tag.SignatureCount()
+
+		if total == 0 {
+			fmt.Printf("No Signatures found.\n")
+			memguard.SafeExit(1)
+		}
+
+		// a Signature of the Digest is valid for the Tag and all its Aliases
+		references := img.GetReferences(tag)
+
+		failed := verifySignatures(ctx, img, tag.ContentDigest, tag.Signatures, references, keyring, "")
+
+		for _, p := range tag.Platforms {
+			if len(p.Signatures) == 0 {
+				fmt.Printf("%-14s %-16s %s (%s) has no Signatures\n", "-", "skipped", p.Platform, p.Digest)
+				continue
+			}
+
+			failed += verifySignatures(ctx, img, p.Digest, p.Signatures, references, keyring, p.Platform)
+		}
+
+		if failed > 0 {
+			fmt.Printf("\n%d of %d Signatures are not valid!\n", failed, total)
+			memguard.SafeExit(1)
+		}
+	},
+}
+
+// verifySignatures verifies the Signatures @names of the Manifest @digest (of the Platform @platform)
+// and prints the Result of every Signature. Returns the Number of invalid Signatures.
+func verifySignatures(ctx context.Context, img *registry.Image, digest string, names []string, references []string,
+	keyring openpgp.KeyRing, platform string) int {
+	var failed int
+
+	for _, name := range names {
+		label := name
+		if len(platform) > 0 { label = fmt.Sprintf("%s [%s]", name, platform) }
+
+		data, err := img.ReadDigestSignature(ctx, digest, name)
+		if err != nil {
+			fmt.Printf("%-14s %-16s %s\n", label, signature.StatusInvalid, err.Error())
+			failed++
+			continue
+		}
+
+		result := signature.Verify(data, keyring, digest)
+		signature.CheckIdentity(result, references)
+
+		if result.Status != signature.StatusValid {
+			failed++
+			fmt.Printf("%-14s %-16s %s\n", label, result.Status, result.Err.Error())
+		} else {
+			fmt.Printf("%-14s %-16s Key %s\n", label, result.Status, result.Signature.KeyID)
+		}
+	}
+
+	return failed
+}
+
+func init() {
+	signatureCmd.AddCommand(verifyCmd)
+}
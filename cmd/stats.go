@@ -22,15 +22,18 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"fmt"
 	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/output"
 	"github.com/fabmation-gmbh/oima/pkg/registry"
 
+	"github.com/awnumar/memguard"
 	"github.com/spf13/cobra"
 
 	"os"
 )
 
+var statsOutput string		// Output Format (table, json, yaml, csv)
+
 // statsCmd represents the stats command
 var statsCmd = &cobra.Command{
 	Use:   "stats",
@@ -38,30 +41,41 @@ var statsCmd = &cobra.Command{
 	Long: `Shows Statistics of the Registry like
 Number of Repositories, Images, Tags, ...`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// ATTENTION: This is only for testing/ debugging!
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		format, err := output.ParseFormat(statsOutput)
+		if err != nil {
+			Log.Error(err.Error())
+			memguard.SafeExit(1)
+		}
+
 		var dockerRegistry registry.DockerRegistry
-		err := dockerRegistry.Init()
+		dockerRegistry.Name = registryName
+		err = dockerRegistry.Init(ctx)
 		if err != nil {
-			Log.Panicf("Error while Initialize DockerRegistry: %s", err.Error())
+			Log.Errorf("Error while Initialize DockerRegistry: %s", err.Error())
+			memguard.SafeExit(1)
 		}
 
-		err = dockerRegistry.FetchAll()
+		err = dockerRegistry.FetchAll(ctx)
 		if err != nil {
-			Log.Fatalf("Error while Fetching All Informations from Registry '%s': %s", dockerRegistry.URI, err.Error())
-			os.Exit(1)
+			Log.Errorf("Error while Fetching All Informations from Registry '%s': %s", dockerRegistry.URI, err.Error())
+			memguard.SafeExit(1)
 		}
 
-		stats := dockerRegistry.Stats()
-		fmt.Printf(
-			"\n\n>>>>> Statistics for Registry %s <<<<<\n\n" +
-				"Repositories: %8d\n" +
-			"Images:       %8d\n" +
-			"Tags:         %8d\n" +
-			"S3Signatures: %8d\n",
-			dockerRegistry.URI, stats.Repos, stats.Images, stats.Tags, stats.S3Signatures)
+		record := output.NewStatsRecord(&dockerRegistry, dockerRegistry.Stats())
+
+		err = output.Write(os.Stdout, format, output.StatsColumns, []output.Record{record})
+		if err != nil {
+			Log.Errorf("Error while writing Output: %s", err.Error())
+			memguard.SafeExit(1)
+		}
 	},
 }
 
 func init() {
 	registryCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().StringVarP(&statsOutput, "output", "o", string(output.Table), "Output Format (table, json, yaml, csv)")
 }
@@ -3,4 +3,6 @@ package log
 import "github.com/apsdehal/go-logger"
 import "os"
 
-var Log, _ = logger.New("oima", 1, os.Stdout)
\ No newline at end of file
+// Log writes to Stderr, so the Output of the Commands (eg. 'image list -o json')
+// can be parsed by Scripts
+var Log, _ = logger.New("oima", 1, os.Stderr)
\ No newline at end of file
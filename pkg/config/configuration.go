@@ -1,9 +1,93 @@
 package config
 
+// Name of the Registry configured in the 'registry' Block
+// (used if no 'registries' List is configured)
+const DefaultRegistryName = "default"
+
 type Configuration struct {
 	// (Docker) Registry Configuration
 	Registry	RegistryConf `mapstructure:"registry"`
 
 	// S3-Server Configuration
 	S3			S3Conf		 `mapstructure:"s3"`
+
+	// List of named (Docker) Registries, replaces the 'registry' Block if set
+	Registries	[]RegistryConf `mapstructure:"registries"`
+
+	// List of named Signature Stores, referenced by the Registries
+	Sigstores	[]SigstoreConf `mapstructure:"sigstores"`
+
+	// Notary-Server Configuration
+	Notary		NotaryConf	 `mapstructure:"notary"`
+
+	// Signature (Keys) Configuration
+	Signature	SignatureConf `mapstructure:"signature"`
+
+	// Retention Policy used by 'oima signature prune'
+	Prune		PruneConf	 `mapstructure:"prune"`
+
+	// Encrypted Credential Vault
+	Credential	CredentialConf `mapstructure:"credential"`
+
+	// Retry Policy of all HTTP and S3 Requests
+	Retry		RetryConf	 `mapstructure:"retry"`
+}
+
+// VaultPath returns the Path of the Credential Vault
+func (c Configuration) VaultPath() string {
+	if len(c.Credential.Vault) > 0 { return c.Credential.Vault }
+
+	return "$HOME/.oima.vault"
+}
+
+// GetRegistries returns all configured Registries. If the 'registries' List
+// is not set, the 'registry' Block is returned as Registry 'default'.
+func (c Configuration) GetRegistries() []RegistryConf {
+	if len(c.Registries) > 0 { return c.Registries }
+
+	reg := c.Registry
+	if len(reg.Name) == 0 { reg.Name = DefaultRegistryName }
+
+	return []RegistryConf{reg}
+}
+
+// GetRegistry returns the Registry with the Name @name,
+// an empty Name selects the first configured Registry
+func (c Configuration) GetRegistry(name string) (RegistryConf, bool) {
+	registries := c.GetRegistries()
+	if len(name) == 0 { return registries[0], true }
+
+	for _, reg := range registries {
+		if reg.Name == name { return reg, true }
+	}
+
+	return RegistryConf{}, false
+}
+
+// GetSigstore returns the Signature Store of the Registry @reg.
+// If @reg does not reference a named Signature Store, the URI is taken
+// from @reg (or the global 's3' Block). An empty URI means that no
+// Signature Store is available.
+func (c Configuration) GetSigstore(reg RegistryConf) SigstoreConf {
+	for _, store := range c.Sigstores {
+		if len(reg.Sigstore) > 0 && store.Name == reg.Sigstore {
+			store.S3.Enabled = store.URI == "s3"
+			return store
+		}
+	}
+
+	// a Lookaside Web-Server configured in the Registry uses the Transport of the Registry
+	store := SigstoreConf{ URI: reg.Sigstore, S3: c.S3, Transport: reg.Transport }
+	if len(store.URI) == 0 && c.S3.Enabled { store.URI = "s3" }
+
+	return store
+}
+
+// CredentialName returns the Name of the Credential @name of the Registry/ Signature Store
+// @owner in the CredStore. The Credentials of the 'default' Registry and of the global
+// 's3' Block (empty @owner) are stored without Suffix (eg. 'password' or 'password/prod').
+func CredentialName(name string, owner string) string {
+	if len(owner) == 0 || owner == DefaultRegistryName { return name }
+
+	return name + "/" + owner
 }
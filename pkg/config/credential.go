@@ -0,0 +1,7 @@
+package config
+
+type CredentialConf struct {
+	// Path of the encrypted Credential Vault (defaults to '$HOME/.oima.vault').
+	// The Vault is managed with 'oima credential'.
+	Vault		string		`mapstructure:"vault"`
+}
@@ -0,0 +1,30 @@
+package config
+
+
+type NotaryConf struct {
+	// Enable or Disable the Notary Component
+	Enabled			bool		`mapstructure:"enabled"`
+
+	// URL of the Notary Server. For Example "https://notary.docker.io"
+	URL				string		`mapstructure:"url"`
+
+	// Username used to authenticate against the Notary Server (optional)
+	Username		string		`mapstructure:"username"`
+
+	// Password used to authenticate against the Notary Server (optional)
+	Password		string		`mapstructure:"password"`
+
+	// Path to the (PEM encoded) private Key of the 'targets' Role.
+	// The Key is needed to re-sign the Targets Metadata after deleting a Target.
+	TargetsKey		string		`mapstructure:"targetsKey"`
+
+	// Path to the (PEM encoded) private Key of the 'targets/releases' Delegation Role.
+	// Docker Content Trust signs the Tags in this Role, so the Key is needed to delete them.
+	ReleasesKey		string		`mapstructure:"releasesKey"`
+
+	// Expiry of the re-signed Targets Metadata (defaults to 3 Years)
+	Expiry			string		`mapstructure:"expiry"`
+
+	// TLS, Proxy and Timeouts of the Notary Requests
+	Transport		TransportConf	`mapstructure:"transport"`
+}
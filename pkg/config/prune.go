@@ -0,0 +1,14 @@
+package config
+
+
+type PruneConf struct {
+	// Keep the Signatures of the newest N (signed) Tags of every Image (0 = disabled)
+	KeepLast		int			`mapstructure:"keepLast"`
+
+	// Keep the Signatures of all Tags matching one of these Regular Expressions
+	// (eg. '^v\d+\.\d+\.\d+$')
+	KeepTags		[]string	`mapstructure:"keepTags"`
+
+	// Drop the Signatures of Tags which were signed before this Duration (eg. '2160h')
+	MaxAge			string		`mapstructure:"maxAge"`
+}
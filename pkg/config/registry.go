@@ -3,8 +3,50 @@ package config
 import ()
 
 type RegistryConf struct {
+	// Name of the Registry (only used in the 'registries' List, eg. 'prod')
+	Name		string	`mapstructure:"name"`
+
 	RegistryURI	string	`mapstructure:"uri"`
 	RequireAuth	string	`mapstructure:"require_auth"`
 	Username	string	`mapstructure:"username"`
 	Password	string	`mapstructure:"password"`
-}
\ No newline at end of file
+
+	// Path of Docker's 'config.json' (defaults to '~/.docker/config.json').
+	// If no Password is configured, the Credentials of 'docker login'
+	// (or the configured Credential Helpers) are used.
+	DockerConfig	string	`mapstructure:"docker_config"`
+
+	// Signature Store of the Registry, possible Values are:
+	//		<name>						Use the Signature Store with this Name in the 'sigstores' List
+	//		s3							Use the S3 Server configured in the 's3' Block
+	//		file:///path/to/sigstore	Use a local Directory
+	//		https://host/sigstore		Use a (read-only) Lookaside Web-Server
+	// If empty, the S3 Server is used if it is enabled.
+	Sigstore	string	`mapstructure:"sigstore"`
+
+	// Number of Entries requested per Page from the '_catalog'
+	// and 'tags/list' Endpoints (defaults to 100)
+	PageSize	int		`mapstructure:"page_size"`
+
+	// Max. Number of concurrent Requests sent to the Registry and
+	// the Signature Store while fetching (defaults to 8)
+	Concurrency	int		`mapstructure:"concurrency"`
+
+	// TLS, Proxy and Timeouts of the Registry and Token Server Requests
+	Transport	TransportConf	`mapstructure:"transport"`
+
+	// How Requests are authenticated, possible Values are:
+	//		auto			Detect the Mode from the Challenge of the Registry
+	//		none			No Authentication
+	//		basic			HTTP Basic Authentication
+	//		bearer			Bearer Token (see 'token_flow')
+	// If empty, 'auto' is used.
+	AuthMode	string	`mapstructure:"auth_mode"`
+
+	// How Bearer Tokens are requested, possible Values are:
+	//		auto			Use 'challenge' if the Registry sends a Bearer Challenge, else 'artifactory'
+	//		challenge		Standard Docker Token Flow (Realm of the 'WWW-Authenticate' Header)
+	//		artifactory		JFrog Artifactory Token Endpoint
+	// If empty, 'auto' is used.
+	TokenFlow	string	`mapstructure:"token_flow"`
+}
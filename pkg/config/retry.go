@@ -0,0 +1,15 @@
+package config
+
+
+type RetryConf struct {
+	// Max. Number of Attempts of a Request to the Registry, Token Server,
+	// Notary Server or Signature Store (defaults to 4, 1 disables Retries)
+	MaxAttempts		int			`mapstructure:"max_attempts"`
+
+	// Delay before the first Retry, it is doubled with every Attempt (defaults to '500ms')
+	BaseDelay		string		`mapstructure:"base_delay"`
+
+	// Max. Delay between two Attempts (defaults to '30s'). If the Server requests
+	// a longer Delay (with 'Retry-After'), the Request is not retried.
+	MaxDelay		string		`mapstructure:"max_delay"`
+}
@@ -19,4 +19,7 @@ type S3Conf struct {
 
 	// BucketName defines the Bucket where the Signatures are stored
 	BucketName		string		`mapstructure:"bucketName"`
+
+	// TLS, Proxy and Timeouts of the S3 Requests
+	Transport		TransportConf	`mapstructure:"transport"`
 }
\ No newline at end of file
@@ -0,0 +1,12 @@
+package config
+
+
+type SignatureConf struct {
+	// Paths to the (armored or binary) GPG Public Keys which are
+	// trusted to sign Images. Used by 'oima signature verify'.
+	Keyring			[]string	`mapstructure:"keyring"`
+
+	// Path to the (armored or binary) GPG Secret Keyring which
+	// contains the signing Keys. Used by 'oima signature sign'.
+	SecretKeyring	string		`mapstructure:"secretKeyring"`
+}
@@ -0,0 +1,19 @@
+package config
+
+// Named Signature Store, referenced by the 'sigstore' Option of a Registry
+type SigstoreConf struct {
+	// Name of the Signature Store (eg. 'prod-bucket')
+	Name		string		`mapstructure:"name"`
+
+	// Type/ Location of the Signature Store, possible Values are:
+	//		s3							Use the S3 Server configured in the 's3' Block of this Entry
+	//		file:///path/to/sigstore	Use a local Directory
+	//		https://host/sigstore		Use a (read-only) Lookaside Web-Server
+	URI			string		`mapstructure:"uri"`
+
+	// S3-Server Configuration (only used if URI is 's3')
+	S3			S3Conf		`mapstructure:"s3"`
+
+	// TLS, Proxy and Timeouts of a Lookaside Web-Server (the S3-Server uses 's3.transport')
+	Transport	TransportConf	`mapstructure:"transport"`
+}
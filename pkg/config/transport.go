@@ -0,0 +1,24 @@
+package config
+
+
+// TransportConf configures the HTTP Client of a Registry, S3-Server,
+// Notary-Server or Signature Store (TLS, Proxy and Timeouts)
+type TransportConf struct {
+	// PEM encoded CA Certificates which are trusted in addition to the System CAs
+	// (eg. the internal CA of the Registry)
+	CAFile				string		`mapstructure:"ca_file"`
+
+	// PEM encoded Client Certificate and Key, sent if the Server requests a Client Certificate (mTLS)
+	ClientCert			string		`mapstructure:"client_cert"`
+	ClientKey			string		`mapstructure:"client_key"`
+
+	// Do not verify the Certificate of the Server (insecure, only use it for Testing!)
+	InsecureSkipVerify	bool		`mapstructure:"insecure_skip_verify"`
+
+	// URI of the HTTP(S) Proxy (eg. 'http://proxy.example.com:3128').
+	// If empty, the Environment Variables HTTPS_PROXY, HTTP_PROXY and NO_PROXY are used.
+	Proxy				string		`mapstructure:"proxy"`
+
+	// Timeout of a single Request (defaults to '60s')
+	Timeout				string		`mapstructure:"timeout"`
+}
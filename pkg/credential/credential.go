@@ -32,9 +32,7 @@ func (cred *CredStore) AddCredential(name string, data []byte) error {
 	memguard.ScrambleBytes(data)
 
 	encryptedData := dataBuf.Seal()
-	if encryptedData == nil {
-		memguard.SafePanic(errors.NewEnclaveEmptyError())
-	}
+	if encryptedData == nil { return errors.NewEnclaveEmptyError() }
 
 	Log.Debugf("Add Credential with Key Name '%s'", name)
 	cred.credentials[name] = encryptedData
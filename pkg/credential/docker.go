@@ -0,0 +1,201 @@
+package credential
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/awnumar/memguard"
+	"github.com/mitchellh/go-homedir"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/errors"
+)
+
+// Key used by Docker for the Docker Hub in the 'auths' Section
+const dockerHubKey = "https://index.docker.io/v1/"
+
+// Prefix of the Credential Helper Binaries (eg. 'docker-credential-pass')
+const credentialHelperPrefix = "docker-credential-"
+
+// Output of a Credential Helper if no Credentials are stored for a Server
+const credentialsNotFound = "credentials not found in native keychain"
+
+// DockerConfig contains the Parts of Docker's 'config.json' which are needed to get Credentials
+type DockerConfig struct {
+	Auths		map[string]dockerAuth	`json:"auths"`			// Credentials stored by 'docker login'
+	CredsStore	string					`json:"credsStore"`		// Default Credential Helper
+	CredHelpers	map[string]string		`json:"credHelpers"`	// Credential Helper per Registry Host
+}
+
+// dockerAuth is a single Entry of the 'auths' Section
+type dockerAuth struct {
+	Auth		string		`json:"auth"`		// base64('<username>:<password>')
+	Username	string		`json:"username"`	// Username (older Docker Versions)
+	Password	string		`json:"password"`	// Password (older Docker Versions)
+}
+
+// helperCredential is the Output of '<helper> get'
+type helperCredential struct {
+	ServerURL	string		`json:"ServerURL"`
+	Username	string		`json:"Username"`
+	Secret		string		`json:"Secret"`
+}
+
+
+/// >>>>>>>>>> Functions <<<<<<<<<< ///
+
+// DefaultDockerConfigPath returns the Path of Docker's 'config.json'
+// ($DOCKER_CONFIG/config.json or ~/.docker/config.json)
+func DefaultDockerConfigPath() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); len(dir) > 0 { return filepath.Join(dir, "config.json") }
+
+	home, err := homedir.Dir()
+	if err != nil { return "" }
+
+	return filepath.Join(home, ".docker", "config.json")
+}
+
+// LoadDockerConfig reads Docker's 'config.json' at @path (the default Path is used if @path is empty)
+func LoadDockerConfig(path string) (*DockerConfig, error) {
+	if len(path) == 0 { path = DefaultDockerConfigPath() }
+
+	data, err := ioutil.ReadFile(os.ExpandEnv(path))
+	if err != nil { return nil, err }
+
+	var conf DockerConfig
+
+	err = json.Unmarshal(data, &conf)
+	if err != nil { return nil, err }
+
+	return &conf, nil
+}
+
+// Lookup returns the Credentials for the Registry @registryURI.
+// The Credential Helper configured for the Host ('credHelpers') is preferred,
+// then the 'auths' Entry and at last the default Credential Helper ('credsStore').
+// The returned Secret must be wiped by the Caller.
+func (d *DockerConfig) Lookup(registryURI string) (username string, secret []byte, err error) {
+	host := registryHost(registryURI)
+
+	for key, helper := range d.CredHelpers {
+		if registryHost(key) != host { continue }
+
+		Log.Debugf("Using Credential Helper '%s' for '%s'", helper, host)
+		return helperGet(helper, key)
+	}
+
+	for key, auth := range d.Auths {
+		if registryHost(key) != host { continue }
+
+		// Docker writes an empty Entry if the Credentials are stored in the 'credsStore'
+		if len(auth.Auth) == 0 && len(auth.Username) == 0 { continue }
+
+		Log.Debugf("Using Docker Credentials of '%s' for '%s'", key, host)
+		return auth.decode()
+	}
+
+	if len(d.CredsStore) > 0 {
+		Log.Debugf("Using default Credential Helper '%s' for '%s'", d.CredsStore, host)
+
+		// Docker stores the Docker Hub Credentials under the old Index URI
+		server := host
+		if host == registryHost(dockerHubKey) { server = dockerHubKey }
+
+		return helperGet(d.CredsStore, server)
+	}
+
+	return "", nil, errors.NewCredentialNotExistsErrorMsg("No Docker Credentials found for '" + host + "'")
+}
+
+// decode returns the Username and Password of the 'auths' Entry
+func (a dockerAuth) decode() (string, []byte, error) {
+	if len(a.Auth) == 0 { return a.Username, []byte(a.Password), nil }
+
+	data, err := base64.StdEncoding.DecodeString(a.Auth)
+	if err != nil { return "", nil, err }
+
+	i := bytes.IndexByte(data, ':')
+	if i < 0 {
+		memguard.WipeBytes(data)
+		return "", nil, errors.NewCredentialNotExistsErrorMsg("Invalid 'auth' Value in Docker Credential Entry")
+	}
+
+	username := string(data[:i])
+	secret := append([]byte{}, data[i+1:]...)
+	memguard.WipeBytes(data)
+
+	return username, secret, nil
+}
+
+// helperGet requests the Credentials of @server from the Credential Helper @helper
+// by speaking the 'docker-credential-<helper> get' Protocol (Server on stdin, JSON on stdout)
+func helperGet(helper string, server string) (string, []byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(credentialHelperPrefix + helper, "get")
+	cmd.Stdin = strings.NewReader(server)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		output := strings.TrimSpace(stdout.String() + stderr.String())
+		if strings.Contains(output, credentialsNotFound) {
+			return "", nil, errors.NewCredentialNotExistsErrorMsg("No Credentials for '" + server + "' in Credential Helper '" + helper + "'")
+		}
+
+		Log.Debugf("Output of Credential Helper '%s': %s", helper, output)
+		return "", nil, err
+	}
+
+	var cred helperCredential
+
+	err = json.Unmarshal(stdout.Bytes(), &cred)
+	memguard.WipeBytes(stdout.Bytes())
+	if err != nil { return "", nil, err }
+
+	return cred.Username, []byte(cred.Secret), nil
+}
+
+// registryHost returns the Host of a Registry URI (or 'auths' Key) and maps
+// the Docker Hub Aliases to one Host
+// Example:
+//		Input:		https://registry.example.com/v1/
+//		Output:		registry.example.com
+func registryHost(uri string) string {
+	host := uri
+	if i := strings.Index(host, "://"); i >= 0 { host = host[i+3:] }
+	if i := strings.Index(host, "/"); i >= 0 { host = host[:i] }
+
+	host = strings.ToLower(host)
+
+	switch host {
+	case "docker.io", "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com", "hub.docker.com":
+		return "index.docker.io"
+	}
+
+	return host
+}
+
+// AddDockerCredential looks up the Credentials of @registryURI in Docker's 'config.json'
+// (or the configured Credential Helpers) and adds the Secret under @name to the CredStore.
+// Returns the Username of the Credentials.
+func (cred *CredStore) AddDockerCredential(name string, registryURI string, configPath string) (string, error) {
+	conf, err := LoadDockerConfig(configPath)
+	if err != nil { return "", err }
+
+	username, secret, err := conf.Lookup(registryURI)
+	if err != nil { return "", err }
+
+	// AddCredential wipes the Secret
+	err = cred.AddCredential(name, secret)
+	if err != nil { return "", err }
+
+	return username, nil
+}
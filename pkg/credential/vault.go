@@ -0,0 +1,177 @@
+package credential
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/awnumar/memguard"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/errors"
+)
+
+// Current Version of the Vault File Format
+const vaultVersion = 1
+
+// Default scrypt Parameters (recommended for interactive Logins)
+const (
+	scryptN			= 32768
+	scryptR			= 8
+	scryptP			= 1
+	vaultKeyLength	= 32
+	vaultSaltLength	= 32
+)
+
+// Maximal scrypt Parameters accepted from a Vault File, scrypt needs 128 * N * r Bytes
+// of Memory, so a crafted Vault File must not be able to exhaust the Memory (max. 512 MiB)
+const (
+	scryptMaxN		= 1 << 18
+	scryptMaxR		= 16
+	scryptMaxP		= 16
+)
+
+// vaultFile is the (JSON encoded) Content of the Vault File.
+// The Credentials are encrypted and authenticated with NaCl secretbox (XSalsa20-Poly1305),
+// the Key is derived from the Passphrase with scrypt.
+type vaultFile struct {
+	Version		int			`json:"version"`	// Version of the File Format
+	N			int			`json:"n"`			// scrypt CPU/ Memory Cost
+	R			int			`json:"r"`			// scrypt Block Size
+	P			int			`json:"p"`			// scrypt Parallelization
+	Salt		[]byte		`json:"salt"`		// scrypt Salt
+	Nonce		[]byte		`json:"nonce"`		// secretbox Nonce
+	Data		[]byte		`json:"data"`		// Encrypted Credentials (JSON Object Name => Secret)
+}
+
+
+/// >>>>>>>>>> Functions <<<<<<<<<< ///
+
+// VaultExists returns true if a Vault File exists at @path
+func VaultExists(path string) bool {
+	_, err := os.Stat(os.ExpandEnv(path))
+	return err == nil
+}
+
+// LoadVault decrypts the Vault File at @path with @passphrase and adds all Credentials to the CredStore.
+// Credentials which already exist in the CredStore are kept.
+func (cred *CredStore) LoadVault(path string, passphrase []byte) error {
+	data, err := ioutil.ReadFile(os.ExpandEnv(path))
+	if err != nil { return err }
+
+	var vault vaultFile
+
+	err = json.Unmarshal(data, &vault)
+	if err != nil { return err }
+
+	if vault.Version != vaultVersion { return errors.NewVaultDecryptErrorMsg("Unsupported Vault Version") }
+	if len(vault.Nonce) != 24 { return errors.NewVaultDecryptErrorMsg("Invalid Vault Nonce") }
+	if vault.N > scryptMaxN || vault.R > scryptMaxR || vault.P > scryptMaxP {
+		return errors.NewVaultDecryptErrorMsg("Unsupported scrypt Parameters")
+	}
+
+	key, err := scrypt.Key(passphrase, vault.Salt, vault.N, vault.R, vault.P, vaultKeyLength)
+	if err != nil { return err }
+	defer memguard.WipeBytes(key)
+
+	var nonce [24]byte
+	var secretKey [32]byte
+	copy(nonce[:], vault.Nonce)
+	copy(secretKey[:], key)
+	defer memguard.WipeBytes(secretKey[:])
+
+	plain, ok := secretbox.Open(nil, vault.Data, &nonce, &secretKey)
+	if !ok { return errors.NewVaultDecryptError() }
+	defer memguard.WipeBytes(plain)
+
+	var secrets map[string][]byte
+
+	err = json.Unmarshal(plain, &secrets)
+	if err != nil { return err }
+
+	for name, secret := range secrets {
+		if _, err := cred.GetCredential(name); err == nil {
+			Log.Debugf("Credential '%s' already exists in the CredStore, ignoring the Vault Entry", name)
+			memguard.WipeBytes(secret)
+			continue
+		}
+
+		// AddCredential wipes the Secret
+		err = cred.AddCredential(name, secret)
+		if err != nil { return err }
+	}
+
+	return nil
+}
+
+// SaveVault encrypts all Credentials of the CredStore with @passphrase and writes them to @path.
+// A new Salt and Nonce is generated every Time.
+func (cred *CredStore) SaveVault(path string, passphrase []byte) error {
+	secrets := make(map[string][]byte, len(cred.credentials))
+	var buffers []*memguard.LockedBuffer
+
+	defer func() { for _, b := range buffers { b.Destroy() } }()
+
+	for name, enclave := range cred.credentials {
+		buf, err := enclave.Open()
+		if err != nil { return err }
+
+		buffers = append(buffers, buf)
+		secrets[name] = buf.Bytes()
+	}
+
+	plain, err := json.Marshal(secrets)
+	if err != nil { return err }
+	defer memguard.WipeBytes(plain)
+
+	vault := vaultFile{
+		Version: vaultVersion,
+		N:       scryptN,
+		R:       scryptR,
+		P:       scryptP,
+		Salt:    make([]byte, vaultSaltLength),
+		Nonce:   make([]byte, 24),
+	}
+
+	if _, err := io.ReadFull(rand.Reader, vault.Salt); err != nil { return err }
+	if _, err := io.ReadFull(rand.Reader, vault.Nonce); err != nil { return err }
+
+	key, err := scrypt.Key(passphrase, vault.Salt, vault.N, vault.R, vault.P, vaultKeyLength)
+	if err != nil { return err }
+	defer memguard.WipeBytes(key)
+
+	var nonce [24]byte
+	var secretKey [32]byte
+	copy(nonce[:], vault.Nonce)
+	copy(secretKey[:], key)
+	defer memguard.WipeBytes(secretKey[:])
+
+	vault.Data = secretbox.Seal(nil, plain, &nonce, &secretKey)
+
+	data, err := json.MarshalIndent(vault, "", "  ")
+	if err != nil { return err }
+
+	// write to a temporary File first, so the Vault is never left half-written
+	path = os.ExpandEnv(path)
+	tmpPath := path + ".tmp"
+
+	err = ioutil.WriteFile(tmpPath, data, 0600)
+	if err != nil { return err }
+
+	return os.Rename(tmpPath, path)
+}
+
+// Names returns the (sorted) Names of all Credentials in the CredStore
+func (cred *CredStore) Names() []string {
+	var names []string
+	for name := range cred.credentials { names = append(names, name) }
+
+	sort.Strings(names)
+
+	return names
+}
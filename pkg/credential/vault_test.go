@@ -0,0 +1,32 @@
+package credential
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadVaultScryptLimits(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oima-vault")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "vault")
+
+	// each of these Parameters would allocate (at least) Gigabytes of Memory
+	for _, v := range []struct{ n, r, p int }{
+		{ 1 << 30, scryptR, scryptP },
+		{ scryptN, 1 << 20, scryptP },
+		{ scryptN, scryptR, 1 << 20 },
+	} {
+		data := fmt.Sprintf(`{"version":%d,"n":%d,"r":%d,"p":%d,"salt":"c2FsdA==","nonce":"%s","data":""}`,
+			vaultVersion, v.n, v.r, v.p, "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA")
+		if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil { t.Fatal(err) }
+
+		if err := new(CredStore).LoadVault(path, []byte("passphrase")); err == nil {
+			t.Errorf("expected an Error for the scrypt Parameters N=%d, r=%d, p=%d", v.n, v.r, v.p)
+		}
+	}
+}
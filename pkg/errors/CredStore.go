@@ -62,4 +62,25 @@ func NewEnclaveEmptyErrorMsg(message string) *EnclaveEmptyError {
 
 func (e *EnclaveEmptyError) Error() string {
 	return e.message
-}
\ No newline at end of file
+}
+/// -=-=-=-=-=-=-=-=-=] VaultDecryptError [-=-=-=-=-=-=-=-=-=
+
+// VaultDecryptError occurs when the Credential Vault could not be decrypted
+// (wrong Passphrase or modified File).
+type VaultDecryptError struct { message string }
+
+func NewVaultDecryptError() *VaultDecryptError {
+	return &VaultDecryptError{
+		message: "Unable to decrypt the Credential Vault (wrong Passphrase or modified File)",
+	}
+}
+
+func NewVaultDecryptErrorMsg(message string) *VaultDecryptError {
+	return &VaultDecryptError{
+		message: message,
+	}
+}
+
+func (e *VaultDecryptError) Error() string {
+	return e.message
+}
@@ -1,5 +1,7 @@
 package errors
 
+import "strconv"
+
 /// -=-=-=-=-=-=-=-=-=] RepositoryNameNotDefinedError [-=-=-=-=-=-=-=-=-=
 
 // RepositoryNameNotDefinedError occurs when the Application tries to get Information
@@ -42,4 +44,236 @@ func NewImageNameNotDefinedErrorMsg(message string) *ImageNameNotDefinedError {
 
 func (e *ImageNameNotDefinedError) Error() string {
 	return e.message
-}
\ No newline at end of file
+}
+
+/// -=-=-=-=-=-=-=-=-=] InvalidReferenceError [-=-=-=-=-=-=-=-=-=
+
+// InvalidReferenceError occurs when the User passes an Image Reference
+// that is neither '<image>:<tag>' nor '<image>@<algo>:<digest>'.
+type InvalidReferenceError struct { message string }
+
+func NewInvalidReferenceError(reference string) *InvalidReferenceError {
+	return &InvalidReferenceError{
+		message: "Invalid Image Reference '" + reference + "' (expected '<image>:<tag>' or '<image>@sha256:<digest>')",
+	}
+}
+
+func NewInvalidReferenceErrorMsg(message string) *InvalidReferenceError {
+	return &InvalidReferenceError{
+		message: message,
+	}
+}
+
+func (e *InvalidReferenceError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] TagNotFoundError [-=-=-=-=-=-=-=-=-=
+
+// TagNotFoundError occurs when the requested Tag (or Digest)
+// does not exists in the Image.
+type TagNotFoundError struct { message string }
+
+func NewTagNotFoundError(image string, tag string) *TagNotFoundError {
+	return &TagNotFoundError{
+		message: "Tag '" + tag + "' of Image '" + image + "' not found",
+	}
+}
+
+func NewTagNotFoundErrorMsg(message string) *TagNotFoundError {
+	return &TagNotFoundError{
+		message: message,
+	}
+}
+
+func (e *TagNotFoundError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] RegistryRequestError [-=-=-=-=-=-=-=-=-=
+
+// RegistryRequestError occurs when the Registry answers
+// with an unexpected HTTP Status Code.
+type RegistryRequestError struct { message string }
+
+func NewRegistryRequestError(uri string, statusCode int) *RegistryRequestError {
+	return &RegistryRequestError{
+		message: "Registry returned Status " + strconv.Itoa(statusCode) + " for '" + uri + "'",
+	}
+}
+
+func NewRegistryRequestErrorMsg(message string) *RegistryRequestError {
+	return &RegistryRequestError{
+		message: message,
+	}
+}
+
+func (e *RegistryRequestError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] RegistryNotFoundError [-=-=-=-=-=-=-=-=-=
+
+// RegistryNotFoundError occurs when a Registry is selected
+// which does not exist in the Configuration.
+type RegistryNotFoundError struct { message string }
+
+func NewRegistryNotFoundError(name string) *RegistryNotFoundError {
+	return &RegistryNotFoundError{
+		message: "Registry '" + name + "' is not configured",
+	}
+}
+
+func NewRegistryNotFoundErrorMsg(message string) *RegistryNotFoundError {
+	return &RegistryNotFoundError{
+		message: message,
+	}
+}
+
+func (e *RegistryNotFoundError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] InvalidFilterError [-=-=-=-=-=-=-=-=-=
+
+// InvalidFilterError occurs when a Filter Pattern (Glob or Regex) can not be compiled.
+type InvalidFilterError struct { message string }
+
+func NewInvalidFilterError(pattern string, err error) *InvalidFilterError {
+	return &InvalidFilterError{
+		message: "Invalid Filter Pattern '" + pattern + "': " + err.Error(),
+	}
+}
+
+func NewInvalidFilterErrorMsg(message string) *InvalidFilterError {
+	return &InvalidFilterError{
+		message: message,
+	}
+}
+
+func (e *InvalidFilterError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] ResourceNotFoundError [-=-=-=-=-=-=-=-=-=
+
+// ResourceNotFoundError occurs when the Registry returns '404 Not Found'
+// for a Repository, Image or Manifest.
+type ResourceNotFoundError struct { message string }
+
+func NewResourceNotFoundError(uri string) *ResourceNotFoundError {
+	return &ResourceNotFoundError{
+		message: "Registry Resource '" + uri + "' not found",
+	}
+}
+
+func NewResourceNotFoundErrorMsg(message string) *ResourceNotFoundError {
+	return &ResourceNotFoundError{
+		message: message,
+	}
+}
+
+func (e *ResourceNotFoundError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] RateLimitError [-=-=-=-=-=-=-=-=-=
+
+// RateLimitError occurs when the Registry returns '429 Too Many Requests'.
+type RateLimitError struct {
+	message		string
+	RetryAfter	string		// Value of the 'Retry-After' Header (may be empty)
+}
+
+func NewRateLimitError(uri string, retryAfter string) *RateLimitError {
+	message := "Registry rate limited the Request '" + uri + "'"
+	if len(retryAfter) > 0 { message += " (Retry-After: " + retryAfter + ")" }
+
+	return &RateLimitError{
+		message:    message,
+		RetryAfter: retryAfter,
+	}
+}
+
+func NewRateLimitErrorMsg(message string) *RateLimitError {
+	return &RateLimitError{
+		message: message,
+	}
+}
+
+func (e *RateLimitError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] ServiceUnavailableError [-=-=-=-=-=-=-=-=-=
+
+// ServiceUnavailableError occurs when a Server is temporarily not available
+// (eg. '502 Bad Gateway', '503 Service Unavailable' or '504 Gateway Timeout').
+type ServiceUnavailableError struct {
+	message		string
+	RetryAfter	string		// Value of the 'Retry-After' Header (may be empty)
+}
+
+func NewServiceUnavailableError(uri string, statusCode int, retryAfter string) *ServiceUnavailableError {
+	message := "Server returned Status " + strconv.Itoa(statusCode) + " for '" + uri + "'"
+	if len(retryAfter) > 0 { message += " (Retry-After: " + retryAfter + ")" }
+
+	return &ServiceUnavailableError{
+		message:    message,
+		RetryAfter: retryAfter,
+	}
+}
+
+func NewServiceUnavailableErrorMsg(message string) *ServiceUnavailableError {
+	return &ServiceUnavailableError{
+		message: message,
+	}
+}
+
+func (e *ServiceUnavailableError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] UnsupportedRegistryVersionError [-=-=-=-=-=-=-=-=-=
+
+// UnsupportedRegistryVersionError occurs when the Registry only supports
+// an API Version which is not supported by oima (eg. 'v1').
+type UnsupportedRegistryVersionError struct { message string }
+
+func NewUnsupportedRegistryVersionError(version string) *UnsupportedRegistryVersionError {
+	return &UnsupportedRegistryVersionError{
+		message: "Registry API Version '" + version + "' is not supported",
+	}
+}
+
+func NewUnsupportedRegistryVersionErrorMsg(message string) *UnsupportedRegistryVersionError {
+	return &UnsupportedRegistryVersionError{
+		message: message,
+	}
+}
+
+func (e *UnsupportedRegistryVersionError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] ComponentDisabledError [-=-=-=-=-=-=-=-=-=
+
+// ComponentDisabledError occurs when a Component (eg. S3 or Notary)
+// is used, but it was disabled in the Configuration.
+type ComponentDisabledError struct { message string }
+
+func NewComponentDisabledError(component string) *ComponentDisabledError {
+	return &ComponentDisabledError{
+		message: "[Internal Error] Component '" + component + "' is used, but it is disabled",
+	}
+}
+
+func NewComponentDisabledErrorMsg(message string) *ComponentDisabledError {
+	return &ComponentDisabledError{
+		message: message,
+	}
+}
+
+func (e *ComponentDisabledError) Error() string {
+	return e.message
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+/// -=-=-=-=-=-=-=-=-=] InvalidConfigValueError [-=-=-=-=-=-=-=-=-=
+
+// InvalidConfigValueError occurs when a Configuration Option
+// has a Value which is not supported.
+type InvalidConfigValueError struct { message string }
+
+func NewInvalidConfigValueError(option string, value string, possible []string) *InvalidConfigValueError {
+	return &InvalidConfigValueError{
+		message: fmt.Sprintf("Invalid Value '%s' for '%s' (possible Values: %s)", value, option, strings.Join(possible, ", ")),
+	}
+}
+
+func NewInvalidConfigValueErrorMsg(message string) *InvalidConfigValueError {
+	return &InvalidConfigValueError{
+		message: message,
+	}
+}
+
+func (e *InvalidConfigValueError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] AuthenticationError [-=-=-=-=-=-=-=-=-=
+
+// AuthenticationError occurs when the Registry rejects the configured
+// Credentials or requires an Authentication which is not possible.
+type AuthenticationError struct { message string }
+
+func NewAuthenticationError(username string, statusCode int) *AuthenticationError {
+	return &AuthenticationError{
+		message: fmt.Sprintf("Registry rejected the Credentials of User '%s' (Status %d)", username, statusCode),
+	}
+}
+
+func NewAuthenticationErrorMsg(message string) *AuthenticationError {
+	return &AuthenticationError{
+		message: message,
+	}
+}
+
+func (e *AuthenticationError) Error() string {
+	return e.message
+}
@@ -0,0 +1,69 @@
+package errors
+
+import "fmt"
+
+/// -=-=-=-=-=-=-=-=-=] NotaryKeyNotDefinedError [-=-=-=-=-=-=-=-=-=
+
+// NotaryKeyNotDefinedError occurs when the Application tries to modify
+// Trust Data but no 'targets' Key was configured.
+type NotaryKeyNotDefinedError struct { message string }
+
+func NewNotaryKeyNotDefinedError() *NotaryKeyNotDefinedError {
+	return &NotaryKeyNotDefinedError{
+		message: "No Notary Targets Key configured, unable to re-sign Trust Data",
+	}
+}
+
+func NewNotaryKeyNotDefinedErrorMsg(message string) *NotaryKeyNotDefinedError {
+	return &NotaryKeyNotDefinedError{
+		message: message,
+	}
+}
+
+func (e *NotaryKeyNotDefinedError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] NotaryInvalidKeyError [-=-=-=-=-=-=-=-=-=
+
+// NotaryInvalidKeyError occurs when the configured 'targets' Key
+// could not be decoded or has an unsupported Type.
+type NotaryInvalidKeyError struct { message string }
+
+func NewNotaryInvalidKeyError() *NotaryInvalidKeyError {
+	return &NotaryInvalidKeyError{
+		message: "Invalid or unsupported Notary Key (only unencrypted ECDSA and Ed25519 PEM Keys are supported)",
+	}
+}
+
+func NewNotaryInvalidKeyErrorMsg(message string) *NotaryInvalidKeyError {
+	return &NotaryInvalidKeyError{
+		message: message,
+	}
+}
+
+func (e *NotaryInvalidKeyError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] NotaryRequestError [-=-=-=-=-=-=-=-=-=
+
+// NotaryRequestError occurs when the Notary Server answers
+// with an unexpected HTTP Status Code.
+type NotaryRequestError struct { message string }
+
+func NewNotaryRequestError(uri string, statusCode int) *NotaryRequestError {
+	return &NotaryRequestError{
+		message: fmt.Sprintf("Notary Server returned Status %d for '%s'", statusCode, uri),
+	}
+}
+
+func NewNotaryRequestErrorMsg(message string) *NotaryRequestError {
+	return &NotaryRequestError{
+		message: message,
+	}
+}
+
+func (e *NotaryRequestError) Error() string {
+	return e.message
+}
@@ -0,0 +1,47 @@
+package errors
+
+import "fmt"
+
+/// -=-=-=-=-=-=-=-=-=] S3AccessDeniedError [-=-=-=-=-=-=-=-=-=
+
+// S3AccessDeniedError occurs when the S3 Server denies the Access
+// to an Object (the Credentials have not the needed Permissions).
+type S3AccessDeniedError struct { message string }
+
+func NewS3AccessDeniedError(object string) *S3AccessDeniedError {
+	return &S3AccessDeniedError{
+		message: fmt.Sprintf("S3 Server denied the Access to '%s'", object),
+	}
+}
+
+func NewS3AccessDeniedErrorMsg(message string) *S3AccessDeniedError {
+	return &S3AccessDeniedError{
+		message: message,
+	}
+}
+
+func (e *S3AccessDeniedError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] S3BucketNotFoundError [-=-=-=-=-=-=-=-=-=
+
+// S3BucketNotFoundError occurs when the configured Bucket does not exist
+// or the Bucket Name is invalid.
+type S3BucketNotFoundError struct { message string }
+
+func NewS3BucketNotFoundError(bucket string) *S3BucketNotFoundError {
+	return &S3BucketNotFoundError{
+		message: fmt.Sprintf("S3 Bucket '%s' not found", bucket),
+	}
+}
+
+func NewS3BucketNotFoundErrorMsg(message string) *S3BucketNotFoundError {
+	return &S3BucketNotFoundError{
+		message: message,
+	}
+}
+
+func (e *S3BucketNotFoundError) Error() string {
+	return e.message
+}
@@ -0,0 +1,67 @@
+package errors
+
+/// -=-=-=-=-=-=-=-=-=] InvalidSignatureError [-=-=-=-=-=-=-=-=-=
+
+// InvalidSignatureError occurs when a Signature Object could not be
+// decoded (no OpenPGP Message, unknown Payload, ...).
+type InvalidSignatureError struct { message string }
+
+func NewInvalidSignatureError() *InvalidSignatureError {
+	return &InvalidSignatureError{
+		message: "Invalid Signature",
+	}
+}
+
+func NewInvalidSignatureErrorMsg(message string) *InvalidSignatureError {
+	return &InvalidSignatureError{
+		message: message,
+	}
+}
+
+func (e *InvalidSignatureError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] SignatureExistsError [-=-=-=-=-=-=-=-=-=
+
+// SignatureExistsError occurs when the Application tries to store a Signature
+// but an identical Signature (same Key, Digest and Reference) already exists.
+type SignatureExistsError struct { message string }
+
+func NewSignatureExistsError(name string) *SignatureExistsError {
+	return &SignatureExistsError{
+		message: "An identical Signature already exists (" + name + ")",
+	}
+}
+
+func NewSignatureExistsErrorMsg(message string) *SignatureExistsError {
+	return &SignatureExistsError{
+		message: message,
+	}
+}
+
+func (e *SignatureExistsError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] KeyNotFoundError [-=-=-=-=-=-=-=-=-=
+
+// KeyNotFoundError occurs when the requested (GPG) Key
+// does not exists in the Keyring.
+type KeyNotFoundError struct { message string }
+
+func NewKeyNotFoundError(keyID string) *KeyNotFoundError {
+	return &KeyNotFoundError{
+		message: "Key '" + keyID + "' not found in the Keyring",
+	}
+}
+
+func NewKeyNotFoundErrorMsg(message string) *KeyNotFoundError {
+	return &KeyNotFoundError{
+		message: message,
+	}
+}
+
+func (e *KeyNotFoundError) Error() string {
+	return e.message
+}
@@ -0,0 +1,119 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+/// -=-=-=-=-=-=-=-=-=] SignatureStoreReadOnlyError [-=-=-=-=-=-=-=-=-=
+
+// SignatureStoreReadOnlyError occurs when the Application tries to write or delete
+// a Signature in a read-only Signature Store (eg. a HTTP Lookaside Server).
+type SignatureStoreReadOnlyError struct { message string }
+
+func NewSignatureStoreReadOnlyError() *SignatureStoreReadOnlyError {
+	return &SignatureStoreReadOnlyError{
+		message: "The Signature Store is read-only",
+	}
+}
+
+func NewSignatureStoreReadOnlyErrorMsg(message string) *SignatureStoreReadOnlyError {
+	return &SignatureStoreReadOnlyError{
+		message: message,
+	}
+}
+
+func (e *SignatureStoreReadOnlyError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] SignatureStoreNotSupportedError [-=-=-=-=-=-=-=-=-=
+
+// SignatureStoreNotSupportedError occurs when the configured Signature Store
+// URI uses an unknown Scheme.
+type SignatureStoreNotSupportedError struct { message string }
+
+func NewSignatureStoreNotSupportedError(uri string) *SignatureStoreNotSupportedError {
+	return &SignatureStoreNotSupportedError{
+		message: fmt.Sprintf("Unsupported Signature Store '%s' (use 's3', 'file://' or 'http(s)://')", uri),
+	}
+}
+
+func NewSignatureStoreNotSupportedErrorMsg(message string) *SignatureStoreNotSupportedError {
+	return &SignatureStoreNotSupportedError{
+		message: message,
+	}
+}
+
+func (e *SignatureStoreNotSupportedError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] SignatureStoreRequestError [-=-=-=-=-=-=-=-=-=
+
+// SignatureStoreRequestError occurs when a HTTP Signature Store answers
+// with an unexpected HTTP Status Code.
+type SignatureStoreRequestError struct { message string }
+
+func NewSignatureStoreRequestError(uri string, statusCode int) *SignatureStoreRequestError {
+	return &SignatureStoreRequestError{
+		message: fmt.Sprintf("Signature Store returned Status %d for '%s'", statusCode, uri),
+	}
+}
+
+func NewSignatureStoreRequestErrorMsg(message string) *SignatureStoreRequestError {
+	return &SignatureStoreRequestError{
+		message: message,
+	}
+}
+
+func (e *SignatureStoreRequestError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] SignatureNotFoundError [-=-=-=-=-=-=-=-=-=
+
+// SignatureNotFoundError occurs when the requested Signature
+// does not exists in the Signature Store.
+type SignatureNotFoundError struct { message string }
+
+func NewSignatureNotFoundError() *SignatureNotFoundError {
+	return &SignatureNotFoundError{
+		message: "The requested Signature was not found",
+	}
+}
+
+func NewSignatureNotFoundErrorMsg(message string) *SignatureNotFoundError {
+	return &SignatureNotFoundError{
+		message: message,
+	}
+}
+
+func (e *SignatureNotFoundError) Error() string {
+	return e.message
+}
+
+/// -=-=-=-=-=-=-=-=-=] SignatureCleanupError [-=-=-=-=-=-=-=-=-=
+
+// SignatureCleanupError occurs when a Manifest was deleted from the Registry,
+// but (some of) its Signatures or its Trust Data could not be deleted.
+type SignatureCleanupError struct { message string }
+
+func NewSignatureCleanupError(errs []error) *SignatureCleanupError {
+	var messages []string
+	for _, v := range errs { messages = append(messages, v.Error()) }
+
+	return &SignatureCleanupError{
+		message: fmt.Sprintf("The Manifest was deleted, but %d Signature Cleanups failed: %s", len(errs), strings.Join(messages, "; ")),
+	}
+}
+
+func NewSignatureCleanupErrorMsg(message string) *SignatureCleanupError {
+	return &SignatureCleanupError{
+		message: message,
+	}
+}
+
+func (e *SignatureCleanupError) Error() string {
+	return e.message
+}
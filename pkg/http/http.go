@@ -1,18 +1,140 @@
 package http
 
-// TODO: Implement more..
-
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/go-resty/resty"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/config"
+	"github.com/fabmation-gmbh/oima/pkg/errors"
 )
 
-// NewClient configures a basic http Client with Timeout, TLS (Validate, ...) etc.
-// so its directly usable and the Configuration of the Client is the same everywhere
-// in the Code
-func NewClient() http.Client {
-	return http.Client{
-		CheckRedirect: nil,
-		Jar:           nil,
-		Timeout:       30,
+// Default Timeouts (the Request Timeout can be configured with 'timeout')
+const (
+	defaultTimeout					= 60 * time.Second	// whole Request (incl. reading the Body)
+	defaultDialTimeout				= 10 * time.Second	// TCP Connect
+	defaultTLSHandshakeTimeout		= 10 * time.Second	// TLS Handshake
+	defaultResponseHeaderTimeout	= 30 * time.Second	// waiting for the Response Header
+	defaultIdleConnTimeout			= 90 * time.Second	// keeping idle Connections open
+)
+
+// Max. Number of idle (re-usable) Connections per Host
+const maxIdleConnsPerHost = 16
+
+// User-Agent of all Requests
+const userAgent = "oima-cli"
+
+
+/// >>>>>>>>>> Functions <<<<<<<<<< ///
+
+// NewTransport returns a Transport with the TLS, Proxy and Timeout Settings of @conf,
+// so the Configuration of all Clients (Registry, Token Server, Notary, S3, ...) is the same
+func NewTransport(conf config.TransportConf) (*http.Transport, error) {
+	tlsConfig, err := newTLSConfig(conf)
+	if err != nil { return nil, err }
+
+	proxy := http.ProxyFromEnvironment
+	if len(conf.Proxy) > 0 {
+		proxyURL, err := url.Parse(conf.Proxy)
+		if err != nil || len(proxyURL.Host) == 0 {
+			return nil, errors.NewInvalidConfigValueErrorMsg(fmt.Sprintf("Invalid Proxy URI '%s'", conf.Proxy))
+		}
+
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   defaultDialTimeout,
+		KeepAlive: 30 * time.Second,
 	}
+
+	return &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   defaultTLSHandshakeTimeout,
+		ResponseHeaderTimeout: defaultResponseHeaderTimeout,
+		IdleConnTimeout:       defaultIdleConnTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+		MaxIdleConnsPerHost:   maxIdleConnsPerHost,
+	}, nil
+}
+
+// NewClient returns a http Client which uses the Transport of @conf (see NewTransport())
+// and the configured Request Timeout. The Client should be shared by all Requests
+// to the same Server, so Connections are re-used.
+func NewClient(conf config.TransportConf) (*http.Client, error) {
+	transport, err := NewTransport(conf)
+	if err != nil { return nil, err }
+
+	timeout := defaultTimeout
+	if len(conf.Timeout) > 0 {
+		timeout, err = time.ParseDuration(conf.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, errors.NewInvalidConfigValueErrorMsg(fmt.Sprintf("Invalid Timeout '%s'", conf.Timeout))
+		}
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}, nil
+}
+
+// NewRestyClient returns a resty Client which sends its Requests with @client
+// (see NewClient()) and sets the User-Agent
+func NewRestyClient(client *http.Client) *resty.Client {
+	restyClient := resty.NewWithClient(client)
+	restyClient.SetHeader("User-Agent", userAgent)
+
+	return restyClient
+}
+
+// newTLSConfig returns the TLS Configuration of @conf
+// (System CAs + 'ca_file', Client Certificate and Verification)
+func newTLSConfig(conf config.TransportConf) (*tls.Config, error) {
+	tlsConfig := &tls.Config{ MinVersion: tls.VersionTLS12 }
+
+	if len(conf.CAFile) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			Log.Debugf("Could not load the System CAs, only trusting '%s': %s", conf.CAFile, err.Error())
+			pool = x509.NewCertPool()
+		}
+
+		pem, err := ioutil.ReadFile(os.ExpandEnv(conf.CAFile))
+		if err != nil { return nil, err }
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.NewInvalidConfigValueErrorMsg(fmt.Sprintf("CA File '%s' contains no PEM encoded Certificate", conf.CAFile))
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if len(conf.ClientCert) > 0 || len(conf.ClientKey) > 0 {
+		if len(conf.ClientCert) == 0 || len(conf.ClientKey) == 0 {
+			return nil, errors.NewInvalidConfigValueErrorMsg("'client_cert' and 'client_key' must be configured together")
+		}
+
+		cert, err := tls.LoadX509KeyPair(os.ExpandEnv(conf.ClientCert), os.ExpandEnv(conf.ClientKey))
+		if err != nil { return nil, err }
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if conf.InsecureSkipVerify {
+		Log.Warning("TLS Certificate Verification is disabled ('insecure_skip_verify')!")
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	return tlsConfig, nil
 }
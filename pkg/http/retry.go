@@ -0,0 +1,248 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-resty/resty"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/config"
+	"github.com/fabmation-gmbh/oima/pkg/errors"
+)
+
+// Default Retry Policy (used if the 'retry' Block is not configured)
+const (
+	defaultMaxAttempts	= 4
+	defaultBaseDelay	= 500 * time.Millisecond
+	defaultMaxDelay		= 30 * time.Second
+)
+
+// RetryPolicy describes how often a failed Request is sent again.
+// The Delay between two Attempts grows exponentially (with Jitter) from
+// @BaseDelay up to @MaxDelay, a 'Retry-After' of the Server is honored.
+type RetryPolicy struct {
+	MaxAttempts		int					// Max. Number of Attempts (1 disables Retries)
+	BaseDelay		time.Duration		// Delay before the first Retry
+	MaxDelay		time.Duration		// Max. Delay between two Attempts
+}
+
+// statusError is returned by an Attempt of Get() if the Server
+// answered with a retryable Status Code
+type statusError struct {
+	uri			string
+	status		int
+	retryAfter	string			// Value of the 'Retry-After' Header (may be empty)
+}
+
+// Retry is the Retry Policy shared by all Registry, Token, Notary and Signature Store Requests.
+// It is set from the Configuration with SetRetryPolicy().
+var Retry = &RetryPolicy{
+	MaxAttempts: defaultMaxAttempts,
+	BaseDelay:   defaultBaseDelay,
+	MaxDelay:    defaultMaxDelay,
+}
+
+
+/// >>>>>>>>>> Functions <<<<<<<<<< ///
+
+// SetRetryPolicy sets the shared Retry Policy from the 'retry' Block of the Configuration
+// (the Default is used for every Value which is not configured)
+func SetRetryPolicy(conf config.RetryConf) error {
+	policy := RetryPolicy{
+		MaxAttempts: defaultMaxAttempts,
+		BaseDelay:   defaultBaseDelay,
+		MaxDelay:    defaultMaxDelay,
+	}
+
+	if conf.MaxAttempts > 0 { policy.MaxAttempts = conf.MaxAttempts }
+
+	if len(conf.BaseDelay) > 0 {
+		delay, err := time.ParseDuration(conf.BaseDelay)
+		if err != nil { return errors.NewInvalidConfigValueErrorMsg(fmt.Sprintf("Invalid Duration '%s' for 'retry.base_delay': %s", conf.BaseDelay, err.Error())) }
+
+		policy.BaseDelay = delay
+	}
+
+	if len(conf.MaxDelay) > 0 {
+		delay, err := time.ParseDuration(conf.MaxDelay)
+		if err != nil { return errors.NewInvalidConfigValueErrorMsg(fmt.Sprintf("Invalid Duration '%s' for 'retry.max_delay': %s", conf.MaxDelay, err.Error())) }
+
+		policy.MaxDelay = delay
+	}
+
+	if policy.BaseDelay <= 0 { policy.BaseDelay = defaultBaseDelay }
+	if policy.MaxDelay < policy.BaseDelay { policy.MaxDelay = policy.BaseDelay }
+
+	Retry = &policy
+
+	return nil
+}
+
+// Do calls @op until it succeeds, returns an Error which is not retryable (see Retryable())
+// or the max. Number of Attempts is reached. The Error of the last Attempt is returned.
+// If the Server requests a longer Delay than @MaxDelay, the Request is not retried.
+func (p *RetryPolicy) Do(ctx context.Context, op func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil { return nil }
+
+		// canceled Requests are never retried
+		if ctx.Err() != nil { return err }
+
+		retry, retryAfter := Retryable(err)
+		if !retry || attempt >= p.MaxAttempts { return err }
+
+		delay := p.backoff(attempt)
+		if retryAfter > 0 {
+			if retryAfter > p.MaxDelay {
+				Log.Debugf("Server requested a Delay of %s (max. %s), giving up: %s", retryAfter, p.MaxDelay, err.Error())
+				return err
+			}
+
+			delay = retryAfter
+		}
+
+		Log.Debugf("Attempt %d/%d failed, retrying in %s: %s", attempt, p.MaxAttempts, delay, err.Error())
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Get sends the GET Request @req to @uri and retries it on transient Network Errors and
+// retryable Status Codes (see RetryableStatus()). The Response of the last Attempt is
+// returned, so the Caller has to check the Status Code (eg. it is still 429 if the
+// Server rate limited all Attempts).
+func (p *RetryPolicy) Get(ctx context.Context, req *resty.Request, uri string) (*resty.Response, error) {
+	var resp *resty.Response
+
+	err := p.Do(ctx, func() error {
+		var err error
+
+		resp, err = req.SetContext(ctx).Get(uri)
+		if err != nil { return err }
+
+		if RetryableStatus(resp.StatusCode()) {
+			return &statusError{ uri: uri, status: resp.StatusCode(), retryAfter: resp.Header().Get("Retry-After") }
+		}
+
+		return nil
+	})
+	if _, ok := err.(*statusError); ok { return resp, nil }
+
+	return resp, err
+}
+
+// backoff returns the Delay after the failed Attempt @attempt:
+// a random Duration between the half and the full exponential Delay
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.MaxDelay
+
+	if shift := uint(attempt - 1); shift < 32 {
+		if d := p.BaseDelay << shift; d > 0 && d < p.MaxDelay { delay = d }
+	}
+
+	half := delay / 2
+
+	return half + time.Duration(rand.Int63n(int64(delay - half) + 1))
+}
+
+// RetryableStatus returns true if a Request which was answered with @status should be sent again
+// (429 Too Many Requests, 502 Bad Gateway, 503 Service Unavailable and 504 Gateway Timeout)
+func RetryableStatus(status int) bool {
+	switch status {
+	case 429, 502, 503, 504:
+		return true
+	}
+
+	return false
+}
+
+// Retryable returns true if a Request which failed with @err should be sent again,
+// and the Delay requested by the Server (0 if the Server did not request one)
+func Retryable(err error) (bool, time.Duration) {
+	switch e := err.(type) {
+	case *statusError:
+		return true, ParseRetryAfter(e.retryAfter)
+	case *errors.RateLimitError:
+		return true, ParseRetryAfter(e.RetryAfter)
+	case *errors.ServiceUnavailableError:
+		return true, ParseRetryAfter(e.RetryAfter)
+	}
+
+	return transientError(err), 0
+}
+
+// ParseRetryAfter returns the Delay of the 'Retry-After' Header @value,
+// which contains Seconds or a HTTP Date. Returns 0 if @value is empty or invalid.
+// Example:
+//		Input:		120
+//		Output:		2m0s
+func ParseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if len(value) == 0 { return 0 }
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 { return 0 }
+
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 { return delay }
+	}
+
+	return 0
+}
+
+// transientError returns true if @err is a Network Error which may not occur
+// again (eg. a Timeout, a refused or a reset Connection)
+func transientError(err error) bool {
+	for err != nil {
+		switch e := err.(type) {
+		case *url.Error:
+			err = e.Err
+		case *net.OpError:
+			err = e.Err
+		case *os.SyscallError:
+			err = e.Err
+		case syscall.Errno:
+			switch e {
+			case syscall.ECONNREFUSED, syscall.ECONNRESET, syscall.ECONNABORTED, syscall.EPIPE:
+				return true
+			}
+
+			return e.Timeout()
+		case *net.DNSError:
+			return e.Timeout() || e.Temporary()
+		case net.Error:
+			return e.Timeout()
+		default:
+			return err == io.EOF || err == io.ErrUnexpectedEOF
+		}
+	}
+
+	return false
+}
+
+func (e *statusError) Error() string {
+	message := fmt.Sprintf("Server returned Status %d for '%s'", e.status, e.uri)
+	if len(e.retryAfter) > 0 { message += " (Retry-After: " + e.retryAfter + ")" }
+
+	return message
+}
@@ -0,0 +1,22 @@
+package notary
+
+import (
+	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
+)
+
+type Notary interface {
+	// Initializes the Notary Datatypes
+	InitNotary() error
+
+	// Lists all trusted Targets (signed Tags) of the Image with the
+	// Globally Unique Name @gun (eg. 'docker.reg.local/nginx')
+	ListTargets(gun string) ([]Target, error)
+
+	// Fetches the Trust Data of all Tags of an Image and sets the Bool
+	// in image.Tags[n].NotarySignFound = "Trust Data Found?"
+	FetchTrustData(image *rt.BaseImage) error
+
+	// Deletes all Targets with the Content Digest @digest from the
+	// Trust Data of @gun and re-signs the 'targets' Role
+	DeleteTarget(gun string, digest string) error
+}
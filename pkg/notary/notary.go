@@ -0,0 +1,307 @@
+// Notary Package adds the Possibility to manage the Trust Data
+// of Images stored on a Notary (TUF) Server
+package notary
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty"
+
+	"github.com/fabmation-gmbh/oima/internal"
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/config"
+	"github.com/fabmation-gmbh/oima/pkg/errors"
+	"github.com/fabmation-gmbh/oima/pkg/http"
+	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
+	"github.com/fabmation-gmbh/oima/pkg/sigstore"
+)
+
+var conf config.Configuration
+
+// Default Expiry of the 'targets' Role Metadata (same as the Notary Client)
+const defaultExpiry = 3 * 365 * 24 * time.Hour
+
+// TUF Roles which contains the Targets of an Image
+const (
+	targetsRole		= "targets"				// top-level Role (signed with the Repository Key)
+	releasesRole	= "targets/releases"	// Delegation Role used by Docker Content Trust
+)
+
+// All Roles which are read (and re-signed) by the Notary Client
+var targetRoles = []string{ targetsRole, releasesRole }
+
+
+func (n *NotaryServer) InitNotary() error {
+	// initialize Config
+	conf = internal.GetConfig()
+
+	// check if Notary is enabled
+	if !conf.Notary.Enabled { return errors.NewComponentDisabledError("notary") }
+
+	n.URL = strings.TrimSuffix(conf.Notary.URL, "/")
+
+	client, err := http.NewClient(conf.Notary.Transport)
+	if err != nil {
+		Log.Errorf("Invalid 'transport' Configuration of the Notary Server: %s", err.Error())
+		return err
+	}
+	n.client = client
+	n.Username = conf.Notary.Username
+	n.Expiry = defaultExpiry
+
+	if len(conf.Notary.Expiry) > 0 {
+		expiry, err := time.ParseDuration(conf.Notary.Expiry)
+		if err != nil {
+			Log.Errorf("Invalid Notary Expiry '%s': %s", conf.Notary.Expiry, err.Error())
+			return err
+		}
+		n.Expiry = expiry
+	}
+
+	// get Password
+	if len(n.Username) > 0 {
+		pwdEnclave, err := internal.Cred.GetCredential("notary_password")
+		if err != nil {
+			Log.Errorf("Error while getting Notary Password from CredStore: %s", err.Error())
+			return err
+		}
+		n.password = pwdEnclave
+	}
+
+	// load 'targets' Key
+	if len(conf.Notary.TargetsKey) > 0 {
+		err := n.LoadTargetsKey(conf.Notary.TargetsKey)
+		if err != nil {
+			Log.Errorf("Error while loading Notary Targets Key '%s': %s", conf.Notary.TargetsKey, err.Error())
+			return err
+		}
+	} else {
+		Log.Debugf("No Notary Targets Key configured, deleting Targets of the 'targets' Role is not possible")
+	}
+
+	// load 'targets/releases' Key
+	if len(conf.Notary.ReleasesKey) > 0 {
+		err := n.LoadReleasesKey(conf.Notary.ReleasesKey)
+		if err != nil {
+			Log.Errorf("Error while loading Notary Releases Key '%s': %s", conf.Notary.ReleasesKey, err.Error())
+			return err
+		}
+	} else {
+		Log.Debugf("No Notary Releases Key configured, deleting Targets of the 'targets/releases' Role is not possible")
+	}
+
+	Log.Debugf("Notary Client initialization finished")
+
+	return nil
+}
+
+// ListTargets lists the Targets of the 'targets' Role and of the
+// 'targets/releases' Delegation Role (Docker Content Trust)
+func (n *NotaryServer) ListTargets(gun string) ([]Target, error) {
+	var targetList []Target
+
+	for _, role := range targetRoles {
+		signedData, err := n.getTargets(gun, role)
+		if err != nil { return nil, err }
+
+		// Image (or Role) has no Trust Data
+		if signedData == nil { continue }
+
+		for name, meta := range signedData.Signed.Targets {
+			hash, ok := meta.Hashes["sha256"]
+			if !ok {
+				Log.Warningf("Target '%s' of '%s' (%s) has no sha256 Hash, skipping it", name, gun, role)
+				continue
+			}
+
+			targetList = append(targetList, Target{
+				Name:   name,
+				Role:   role,
+				Digest: fmt.Sprintf("sha256:%s", hex.EncodeToString(hash)),
+				Length: meta.Length,
+			})
+		}
+	}
+
+	return targetList, nil
+}
+
+func (n *NotaryServer) FetchTrustData(image *rt.BaseImage) error {
+	Log.Debugf("Fetching Trust Data for Image %s", (*image).GetName())
+
+	tags := (*image).GetTags()
+
+	trustedTargets, err := n.ListTargets(GUN((*image).GetRegistryURI(), (*image).GetName()))
+	if err != nil { return err }
+
+	// collect all trusted Digests
+	digests := make(map[string]bool)
+	for _, v := range trustedTargets { digests[v.Digest] = true }
+
+	for iTag := range tags {
+		tags[iTag].NotarySignFound = digests[tags[iTag].ContentDigest]
+	}
+
+	// update Tags from Image
+	(*image).SetTags(tags)
+
+	return nil
+}
+
+// DeleteTarget removes the Targets pointing to @digest from the 'targets' Role and the
+// 'targets/releases' Delegation Role. Every changed Role is re-signed with its own Key,
+// nothing is changed if the Key of a Role containing @digest is not configured.
+func (n *NotaryServer) DeleteTarget(gun string, digest string) error {
+	if n.targetsKey == nil && n.releasesKey == nil { return errors.NewNotaryKeyNotDefinedError() }
+
+	hash, err := hex.DecodeString(strings.TrimPrefix(digest, "sha256:"))
+	if err != nil { return err }
+
+	keys := map[string]*roleKey{ targetsRole: n.targetsKey, releasesRole: n.releasesKey }
+	changed := make(map[string]*signedTargets)
+
+	for _, role := range targetRoles {
+		signedData, err := n.getTargets(gun, role)
+		if err != nil { return err }
+
+		if signedData == nil {
+			Log.Debugf("'%s' has no Trust Data in Role '%s'", gun, role)
+			continue
+		}
+
+		// remove all Targets that points to @digest
+		var deleted int
+		for name, meta := range signedData.Signed.Targets {
+			if bytes.Equal(meta.Hashes["sha256"], hash) {
+				Log.Debugf("Removing Target '%s' from '%s' (%s)", name, gun, role)
+				delete(signedData.Signed.Targets, name)
+				deleted++
+			}
+		}
+
+		if deleted == 0 { continue }
+
+		if keys[role] == nil {
+			return errors.NewNotaryKeyNotDefinedErrorMsg(fmt.Sprintf("%s of '%s' is signed in the Role '%s', but no Key of this Role is configured",
+				digest, gun, role))
+		}
+
+		// re-sign the Role
+		signedData.Signed.Version++
+		signedData.Signed.Expires = time.Now().UTC().Add(n.Expiry).Round(time.Second)
+
+		err = signTargets(signedData, keys[role])
+		if err != nil { return err }
+
+		changed[role] = signedData
+	}
+
+	if len(changed) == 0 {
+		Log.Debugf("No Target of '%s' points to %s", gun, digest)
+		return nil
+	}
+
+	return n.publishTargets(gun, changed)
+}
+
+
+/// >>>>> Internal Functions <<<<<
+
+// GUN returns the Globally Unique Name of an Image
+// Example:
+//		Input:		https://docker.internal.int/, nginx
+//		Output:		docker.internal.int/nginx
+func GUN(registryURI string, image string) string {
+	return sigstore.ImagePath(registryURI, image)
+}
+
+// newClient returns a resty Client which is ready to talk with the Notary Server
+func (n *NotaryServer) newClient() (*resty.Client, error) {
+	client := http.NewRestyClient(n.client)
+
+	if len(n.Username) > 0 && n.password != nil {
+		password, err := n.password.Open()
+		if err != nil {
+			Log.Errorf("Error while opening the Password of the Notary User: %s", err.Error())
+			return nil, err
+		}
+		defer password.Destroy()
+
+		// String() points to the Buffer, which is wiped by Destroy()
+		client.SetBasicAuth(n.Username, string(password.Bytes()))
+	}
+
+	return client, nil
+}
+
+// getTargets fetches the current Metadata of the Role @role (eg. 'targets/releases') of @gun.
+// It returns nil (and no Error) if no Trust Data exists for @gun (or @role).
+func (n *NotaryServer) getTargets(gun string, role string) (*signedTargets, error) {
+	uri := fmt.Sprintf("%s/v2/%s/_trust/tuf/%s.json", n.URL, gun, role)
+
+	client, err := n.newClient()
+	if err != nil { return nil, err }
+
+	// the Notary Client does not support Contexts (yet)
+	resp, err := http.Retry.Get(context.Background(), client.R(), uri)
+	if err != nil {
+		Log.Errorf("Error while fetching Targets of '%s' (%s): %s", gun, role, err.Error())
+		return nil, err
+	}
+
+	if resp.StatusCode() == 404 { return nil, nil }
+	if resp.StatusCode() != 200 {
+		return nil, errors.NewNotaryRequestError(uri, resp.StatusCode())
+	}
+
+	var signedData signedTargets
+	err = json.Unmarshal(resp.Body(), &signedData)
+	if err != nil {
+		Log.Debugf("Response: %s", resp.Body())
+		Log.Errorf("Error while marshaling Response: %s", err.Error())
+		return nil, err
+	}
+
+	if signedData.Signed.Targets == nil { signedData.Signed.Targets = make(map[string]fileMeta) }
+
+	return &signedData, nil
+}
+
+// publishTargets uploads the (re-signed) Metadata of all Roles in @roles of @gun in a single Update.
+// The Notary Server generates the new 'snapshot' and 'timestamp' Metadata.
+func (n *NotaryServer) publishTargets(gun string, roles map[string]*signedTargets) error {
+	uri := fmt.Sprintf("%s/v2/%s/_trust/tuf/", n.URL, gun)
+
+	client, err := n.newClient()
+	if err != nil { return err }
+
+	req := client.R()
+	for _, role := range targetRoles {
+		signedData, ok := roles[role]
+		if !ok { continue }
+
+		data, err := json.Marshal(signedData)
+		if err != nil { return err }
+
+		req.SetMultipartField("files", role, "application/json", bytes.NewReader(data))
+	}
+
+	resp, err := req.Post(uri)
+	if err != nil {
+		Log.Errorf("Error while publishing Targets of '%s': %s", gun, err.Error())
+		return err
+	}
+
+	if resp.StatusCode() != 200 {
+		Log.Debugf("Response: %s", resp.Body())
+		return errors.NewNotaryRequestError(uri, resp.StatusCode())
+	}
+
+	return nil
+}
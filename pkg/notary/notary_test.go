@@ -0,0 +1,275 @@
+package notary
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/awnumar/memguard"
+)
+
+const (
+	gun			= "docker.reg.local/team/app"
+	released	= "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	trusted		= "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+// fakeNotary is a minimal Notary Server, which serves the Metadata of the
+// 'targets' and 'targets/releases' Roles and records the published Roles
+type fakeNotary struct {
+	lock		sync.Mutex					// Protects roles and published
+	roles		map[string]*signedTargets	// current Metadata of all Roles
+	published	map[string]*signedTargets	// Roles of the last Update
+}
+
+func (f *fakeNotary) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	prefix := "/v2/" + gun + "/_trust/tuf/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		role := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, prefix), ".json")
+
+		signedData, ok := f.roles[role]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(signedData)
+
+	case http.MethodPost:
+		reader, err := r.MultipartReader()
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		f.published = make(map[string]*signedTargets)
+		for {
+			part, err := reader.NextPart()
+			if err != nil { break }
+
+			// Part.FileName() strips the Directory ('targets/')
+			_, params, _ := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+
+			var signedData signedTargets
+			if err := json.NewDecoder(part).Decode(&signedData); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			f.roles[params["filename"]] = &signedData
+			f.published[params["filename"]] = &signedData
+		}
+	}
+}
+
+// newTargets returns the Metadata of a Role with the Target @name pointing to @digest
+func newTargets(name string, digest string) *signedTargets {
+	hash, _ := hex.DecodeString(strings.TrimPrefix(digest, "sha256:"))
+
+	return &signedTargets{ Signed: targets{
+		Type:    "Targets",
+		Expires: time.Now().Add(time.Hour).UTC().Round(time.Second),
+		Targets: map[string]fileMeta{ name: { Hashes: map[string][]byte{ "sha256": hash }, Length: 1 } },
+		Version: 1,
+	}}
+}
+
+// newTestServer returns a Notary Client for a fake Notary Server, which contains the
+// Target 'v1' in the 'targets' Role and the Target 'v2' in the 'targets/releases' Role
+func newTestServer(t *testing.T) (*NotaryServer, *fakeNotary, func()) {
+	fake := &fakeNotary{ roles: map[string]*signedTargets{
+		targetsRole:  newTargets("v1", trusted),
+		releasesRole: newTargets("v2", released),
+	}}
+	server := httptest.NewServer(fake)
+
+	n := &NotaryServer{ URL: server.URL, Expiry: time.Hour, client: server.Client() }
+
+	return n, fake, server.Close
+}
+
+// newKey returns a new ECDSA Key and sets it as Key of @role
+func newKey(t *testing.T, n *NotaryServer, role string) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil { t.Fatal(err) }
+
+	if role == targetsRole {
+		err = n.SetTargetsKey(key)
+	} else {
+		err = n.SetReleasesKey(key)
+	}
+	if err != nil { t.Fatal(err) }
+
+	return key
+}
+
+// verify checks that @signedData is signed by @key
+func verify(t *testing.T, signedData *signedTargets, key *ecdsa.PrivateKey) {
+	if len(signedData.Signatures) != 1 { t.Fatalf("expected 1 Signature, got %d", len(signedData.Signatures)) }
+
+	msg, err := canonicalJSON(signedData.Signed)
+	if err != nil { t.Fatal(err) }
+
+	digest := sha256.Sum256(msg)
+	sig := signedData.Signatures[0].Signature
+	r, s := new(big.Int).SetBytes(sig[:len(sig)/2]), new(big.Int).SetBytes(sig[len(sig)/2:])
+
+	if !ecdsa.Verify(&key.PublicKey, digest[:], r, s) { t.Errorf("invalid Signature of the re-signed Role") }
+}
+
+func TestListTargets(t *testing.T) {
+	n, _, closeServer := newTestServer(t)
+	defer closeServer()
+
+	list, err := n.ListTargets(gun)
+	if err != nil { t.Fatal(err) }
+
+	found := make(map[string]Target)
+	for _, v := range list { found[v.Name] = v }
+
+	if found["v1"].Role != targetsRole || found["v1"].Digest != trusted {
+		t.Errorf("unexpected Target 'v1': %+v", found["v1"])
+	}
+	if found["v2"].Role != releasesRole || found["v2"].Digest != released {
+		t.Errorf("unexpected Target 'v2': %+v", found["v2"])
+	}
+}
+
+func TestListTargetsNoTrustData(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	n := &NotaryServer{ URL: server.URL, client: server.Client() }
+
+	list, err := n.ListTargets(gun)
+	if err != nil || len(list) != 0 { t.Errorf("expected no Targets, got %v (%v)", list, err) }
+}
+
+func TestDeleteTarget(t *testing.T) {
+	n, fake, closeServer := newTestServer(t)
+	defer closeServer()
+
+	key := newKey(t, n, targetsRole)
+
+	if err := n.DeleteTarget(gun, trusted); err != nil { t.Fatal(err) }
+
+	published, ok := fake.published[targetsRole]
+	if !ok || len(fake.published) != 1 { t.Fatalf("expected only the 'targets' Role to be published, got %v", fake.published) }
+
+	if _, ok := published.Signed.Targets["v1"]; ok { t.Errorf("Target 'v1' was not deleted") }
+	if published.Signed.Version != 2 { t.Errorf("expected Version 2, got %d", published.Signed.Version) }
+	verify(t, published, key)
+}
+
+func TestDeleteTargetDelegation(t *testing.T) {
+	n, fake, closeServer := newTestServer(t)
+	defer closeServer()
+
+	newKey(t, n, targetsRole)
+	key := newKey(t, n, releasesRole)
+
+	if err := n.DeleteTarget(gun, released); err != nil { t.Fatal(err) }
+
+	published, ok := fake.published[releasesRole]
+	if !ok || len(fake.published) != 1 { t.Fatalf("expected only the 'targets/releases' Role to be published, got %v", fake.published) }
+
+	if _, ok := published.Signed.Targets["v2"]; ok { t.Errorf("Target 'v2' was not deleted") }
+	verify(t, published, key)
+
+	// the Trust Data is gone
+	list, err := n.ListTargets(gun)
+	if err != nil { t.Fatal(err) }
+	for _, v := range list {
+		if v.Digest == released { t.Errorf("Target '%s' still exists", v.Name) }
+	}
+}
+
+func TestDeleteTargetDelegationWithoutKey(t *testing.T) {
+	n, fake, closeServer := newTestServer(t)
+	defer closeServer()
+
+	newKey(t, n, targetsRole)
+
+	// the Target is only signed in the Delegation, so it must not be reported as deleted
+	if err := n.DeleteTarget(gun, released); err == nil { t.Errorf("expected an Error, the 'targets/releases' Key is missing") }
+	if fake.published != nil { t.Errorf("nothing should be published, got %v", fake.published) }
+}
+
+func TestDeleteTargetWithoutKey(t *testing.T) {
+	n, _, closeServer := newTestServer(t)
+	defer closeServer()
+
+	if err := n.DeleteTarget(gun, trusted); err == nil { t.Errorf("expected an Error, no Key is configured") }
+}
+
+func TestLoadReleasesKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil { t.Fatal(err) }
+
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil { t.Fatal(err) }
+
+	file, err := ioutil.TempFile("", "releases-*.pem")
+	if err != nil { t.Fatal(err) }
+	defer os.Remove(file.Name())
+
+	err = pem.Encode(file, &pem.Block{ Type: "EC PRIVATE KEY", Bytes: der })
+	file.Close()
+	if err != nil { t.Fatal(err) }
+
+	var n NotaryServer
+	if err := n.LoadReleasesKey(file.Name()); err != nil { t.Fatal(err) }
+
+	expected, err := newRoleKey(key)
+	if err != nil { t.Fatal(err) }
+
+	if n.releasesKey == nil || n.releasesKey.id != expected.id { t.Errorf("unexpected Releases Key %+v", n.releasesKey) }
+	if n.targetsKey != nil { t.Errorf("the 'targets' Key must not be set") }
+}
+
+func TestListTargetsBasicAuth(t *testing.T) {
+	fake := &fakeNotary{ roles: map[string]*signedTargets{ targetsRole: newTargets("v1", trusted) } }
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "notary" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		fake.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	n := &NotaryServer{ URL: server.URL, Username: "notary", client: server.Client() }
+	n.password = memguard.NewEnclave([]byte("secret"))
+
+	// the Password must still be valid after the Enclave Buffer was destroyed
+	for i := 0; i < 2; i++ {
+		list, err := n.ListTargets(gun)
+		if err != nil { t.Fatal(err) }
+		if len(list) != 1 { t.Errorf("expected 1 Target, got %v", list) }
+	}
+}
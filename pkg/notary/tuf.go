@@ -0,0 +1,165 @@
+package notary
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/fabmation-gmbh/oima/pkg/errors"
+)
+
+// This File contains the TUF specific Functions (Keys, Signing, canonical JSON)
+
+// LoadTargetsKey loads the (unencrypted, PEM encoded) private Key
+// of the 'targets' Role from @path.
+// Supported are ECDSA and Ed25519 Keys (PKCS#8 or SEC 1).
+func (n *NotaryServer) LoadTargetsKey(path string) error {
+	key, err := readKey(path)
+	if err != nil { return err }
+
+	return n.SetTargetsKey(key)
+}
+
+// LoadReleasesKey loads the (unencrypted, PEM encoded) private Key of the
+// 'targets/releases' Delegation Role (used by Docker Content Trust) from @path
+func (n *NotaryServer) LoadReleasesKey(path string) error {
+	key, err := readKey(path)
+	if err != nil { return err }
+
+	return n.SetReleasesKey(key)
+}
+
+// SetTargetsKey sets the private Key of the 'targets' Role
+// and calculates the TUF Key ID of it
+func (n *NotaryServer) SetTargetsKey(key interface{}) error {
+	k, err := newRoleKey(key)
+	if err != nil { return err }
+
+	n.targetsKey = k
+	return nil
+}
+
+// SetReleasesKey sets the private Key of the 'targets/releases' Delegation Role
+// and calculates the TUF Key ID of it
+func (n *NotaryServer) SetReleasesKey(key interface{}) error {
+	k, err := newRoleKey(key)
+	if err != nil { return err }
+
+	n.releasesKey = k
+	return nil
+}
+
+// readKey reads the (unencrypted, PEM encoded) private Key @path
+func readKey(path string) (interface{}, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil { return nil, err }
+
+	block, _ := pem.Decode(data)
+	if block == nil { return nil, errors.NewNotaryInvalidKeyError() }
+
+	if block.Type == "EC PRIVATE KEY" { return x509.ParseECPrivateKey(block.Bytes) }
+
+	return x509.ParsePKCS8PrivateKey(block.Bytes)
+}
+
+// newRoleKey returns the Role Key of the private Key @key
+// with the TUF Key ID (sha256 Hash of the canonical Public Key)
+func newRoleKey(key interface{}) (*roleKey, error) {
+	var keyType string
+	var public []byte
+	var signer crypto.Signer
+
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalPKIXPublicKey(&k.PublicKey)
+		if err != nil { return nil, err }
+
+		keyType, public, signer = "ecdsa", der, k
+	case ed25519.PrivateKey:
+		keyType, public, signer = "ed25519", k.Public().(ed25519.PublicKey), k
+	default:
+		return nil, errors.NewNotaryInvalidKeyError()
+	}
+
+	tufKey := map[string]interface{}{
+		"keytype": keyType,
+		"keyval":  map[string]interface{}{ "private": nil, "public": public },
+	}
+
+	data, err := canonicalJSON(tufKey)
+	if err != nil { return nil, err }
+
+	sum := sha256.Sum256(data)
+
+	return &roleKey{ signer: signer, id: hex.EncodeToString(sum[:]) }, nil
+}
+
+// signTargets replaces all Signatures of @signedData with
+// a new Signature created with @key
+func signTargets(signedData *signedTargets, key *roleKey) error {
+	msg, err := canonicalJSON(signedData.Signed)
+	if err != nil { return err }
+
+	var sig signature
+	sig.KeyID = key.id
+
+	switch k := key.signer.(type) {
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256(msg)
+
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil { return err }
+
+		// TUF (Notary) expects the raw Signature (r || s)
+		size := (k.Curve.Params().BitSize + 7) / 8
+		sig.Method = "ecdsa"
+		sig.Signature = append(padInt(r, size), padInt(s, size)...)
+	case ed25519.PrivateKey:
+		sig.Method = "eddsa"
+		sig.Signature, err = k.Sign(rand.Reader, msg, crypto.Hash(0))
+		if err != nil { return err }
+	default:
+		return errors.NewNotaryInvalidKeyError()
+	}
+
+	signedData.Signatures = []signature{sig}
+
+	return nil
+}
+
+// canonicalJSON encodes @v as canonical JSON (sorted Keys, no Whitespace)
+// which is the Input for all TUF Signatures
+func canonicalJSON(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil { return nil, err }
+
+	// decode into generic Types, so that all Object Keys get sorted
+	var generic interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&generic); err != nil { return nil, err }
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err = encoder.Encode(generic); err != nil { return nil, err }
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// padInt returns the big-endian Bytes of @i left padded to @size Bytes
+func padInt(i *big.Int, size int) []byte {
+	b := i.Bytes()
+	if len(b) >= size { return b }
+
+	return append(make([]byte, size-len(b)), b...)
+}
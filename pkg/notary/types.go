@@ -0,0 +1,70 @@
+package notary
+
+import (
+	"crypto"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/awnumar/memguard"
+)
+
+// NotaryServer holds all Informations that are needed
+// to talk with a Notary (TUF) Server
+//noinspection GoNameStartsWithPackageName
+type NotaryServer struct {
+	URL				string				// URL of the Notary Server
+	Username		string				// Username (Authentication is disabled if empty)
+	Expiry			time.Duration		// Expiry of re-signed Targets Metadata
+
+	password		*memguard.Enclave	// Password stored securely
+	targetsKey		*roleKey			// Private Key of the 'targets' Role
+	releasesKey		*roleKey			// Private Key of the 'targets/releases' Delegation Role
+	client			*http.Client		// Shared by all Requests to the Notary Server
+}
+
+// roleKey is the private Key of a TUF Role
+type roleKey struct {
+	signer			crypto.Signer		// Private Key
+	id				string				// TUF Key ID of the Public Key
+}
+
+// Target is a single trusted Tag of an Image
+type Target struct {
+	Name			string				// Name of the Target (eg. 'v1.0.0')
+	Role			string				// TUF Role which signed the Target (eg. 'targets/releases')
+	Digest			string				// Content Digest of the Target (eg. 'sha256:92c7...')
+	Length			int64				// Size of the Manifest
+}
+
+
+/// >>>>> TUF Metadata <<<<<
+
+// signedTargets is the Envelope of the 'targets' Role Metadata
+type signedTargets struct {
+	Signed			targets				`json:"signed"`
+	Signatures		[]signature			`json:"signatures"`
+}
+
+// targets contains all signed Targets of a GUN
+type targets struct {
+	Type			string				`json:"_type"`
+	Delegations		json.RawMessage		`json:"delegations"`
+	Expires			time.Time			`json:"expires"`
+	Targets			map[string]fileMeta	`json:"targets"`
+	Version			int					`json:"version"`
+}
+
+// fileMeta describes the Manifest a Target points to
+type fileMeta struct {
+	Custom			*json.RawMessage	`json:"custom,omitempty"`
+	Hashes			map[string][]byte	`json:"hashes"`
+	Length			int64				`json:"length"`
+}
+
+// signature is a Signature of the canonical 'signed' Part of a Metadata File
+type signature struct {
+	KeyID			string				`json:"keyid"`
+	Method			string				`json:"method"`
+	Signature		[]byte				`json:"sig"`
+}
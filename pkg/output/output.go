@@ -0,0 +1,194 @@
+// Output Package writes the Results of Commands in a Format which
+// can be read by Humans (table) or parsed by Scripts (json, yaml, csv)
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/fabmation-gmbh/oima/pkg/errors"
+	"github.com/fabmation-gmbh/oima/pkg/registry"
+	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
+)
+
+// Formats lists all supported Formats
+var Formats = []string{string(Table), string(JSON), string(YAML), string(CSV)}
+
+// ParseFormat returns the Format @format
+func ParseFormat(format string) (Format, error) {
+	for _, v := range Formats {
+		if strings.ToLower(format) == v { return Format(v), nil }
+	}
+
+	return "", errors.NewInvalidConfigValueError("--output", format, Formats)
+}
+
+// Write writes the @records (with the Header @columns) in @format to @w
+func Write(w io.Writer, format Format, columns []string, records []Record) error {
+	// encode an empty List instead of 'null'
+	if records == nil { records = []Record{} }
+
+	switch format {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(records)
+
+	case YAML:
+		data, err := yaml.Marshal(records)
+		if err != nil { return err }
+
+		_, err = w.Write(data)
+		return err
+
+	case CSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil { return err }
+
+		for _, r := range records {
+			if err := cw.Write(r.Values()); err != nil { return err }
+		}
+		cw.Flush()
+
+		return cw.Error()
+
+	default:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(columns, "\t"))
+
+		for _, r := range records { fmt.Fprintln(tw, strings.Join(r.Values(), "\t")) }
+
+		return tw.Flush()
+	}
+}
+
+// ImageRecords returns one Record per Tag of all (already fetched) Images in the Registry @r
+func ImageRecords(r *registry.DockerRegistry) []Record {
+	var records []Record
+
+	for _, repo := range r.Repos {
+		for iImg := range repo.Images {
+			img := &repo.Images[iImg]
+
+			for iTag := range img.Tags {
+				tag := &img.Tags[iTag]
+				records = append(records, NewImageRecord(r, repo.Name, img.Name, *tag, img.Aliases(tag)))
+			}
+		}
+	}
+
+	// the Images are fetched concurrently, so sort the Records to get a stable Output
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i].(ImageRecord), records[j].(ImageRecord)
+
+		if a.Repository != b.Repository { return a.Repository < b.Repository }
+		if a.Image != b.Image { return a.Image < b.Image }
+
+		return a.Tag < b.Tag
+	})
+
+	return records
+}
+
+// NewImageRecord returns the Record of the Tag @tag (with the Aliases @aliases) of the Image @image
+func NewImageRecord(r *registry.DockerRegistry, repository string, image string, tag rt.Tag, aliases []rt.TagName) ImageRecord {
+	backends := []string{}
+	if tag.SignatureCount() > 0 { backends = append(backends, BackendSigstore) }
+	if tag.NotarySignFound { backends = append(backends, BackendNotary) }
+
+	aliasNames := []string{}
+	for _, v := range aliases { aliasNames = append(aliasNames, string(v)) }
+
+	var platforms []PlatformRecord
+	for _, p := range tag.Platforms {
+		platforms = append(platforms, PlatformRecord{
+			Platform:       p.Platform,
+			Digest:         p.Digest,
+			SignatureCount: len(p.Signatures),
+		})
+	}
+
+	return ImageRecord{
+		Registry:          r.Name,
+		Repository:        repository,
+		Image:             image,
+		Tag:               string(tag.Name),
+		Digest:            tag.ContentDigest,
+		SignatureCount:    tag.SignatureCount(),
+		SignatureBackends: backends,
+		Aliases:           aliasNames,
+		MediaType:         tag.MediaType,
+		Platforms:         platforms,
+	}
+}
+
+// NewManifestRecord returns the Record of the Manifest @manifest of the Tag @tag of the Image @image
+func NewManifestRecord(r *registry.DockerRegistry, image string, tag rt.Tag, manifest rt.ManifestInfo) ManifestRecord {
+	created := ""
+	if !manifest.Created.IsZero() { created = manifest.Created.UTC().Format(time.RFC3339) }
+
+	layers := []LayerRecord{}
+	for _, l := range manifest.Layers {
+		layers = append(layers, LayerRecord{
+			Digest:    l.Digest,
+			MediaType: l.MediaType,
+			Size:      l.Size,
+		})
+	}
+
+	return ManifestRecord{
+		Registry:     r.Name,
+		Image:        image,
+		Tag:          string(tag.Name),
+		Platform:     manifest.Platform,
+		Digest:       manifest.Digest,
+		Created:      created,
+		Size:         manifest.Size,
+		MediaType:    manifest.MediaType,
+		Architecture: manifest.Architecture,
+		OS:           manifest.OS,
+		Layers:       layers,
+		Labels:       manifest.Labels,
+	}
+}
+
+// NewStatsRecord returns the Record of the Statistics @stats of the Registry @r
+func NewStatsRecord(r *registry.DockerRegistry, stats rt.Stats) StatsRecord {
+	return StatsRecord{
+		Registry:         r.Name,
+		URI:              r.URI,
+		Repositories:     stats.Repos,
+		Images:           stats.Images,
+		Tags:             stats.Tags,
+		Signatures:       stats.S3Signatures,
+		NotarySignatures: stats.NotarySignatures,
+	}
+}
+
+// Values returns the Fields of the Record in the Order of ImageColumns
+func (i ImageRecord) Values() []string {
+	return []string{i.Registry, i.Repository, i.Image, i.Tag, i.Digest,
+		strconv.Itoa(i.SignatureCount), strings.Join(i.SignatureBackends, ","), strings.Join(i.Aliases, ",")}
+}
+
+// Values returns the Fields of the Record in the Order of ManifestColumns
+func (m ManifestRecord) Values() []string {
+	return []string{m.Registry, m.Image, m.Tag, m.Platform, m.Digest, m.Created,
+		strconv.FormatInt(m.Size, 10), strconv.Itoa(len(m.Layers))}
+}
+
+// Values returns the Fields of the Record in the Order of StatsColumns
+func (s StatsRecord) Values() []string {
+	return []string{s.Registry, s.URI, strconv.Itoa(s.Repositories), strconv.Itoa(s.Images),
+		strconv.Itoa(s.Tags), strconv.Itoa(s.Signatures), strconv.Itoa(s.NotarySignatures)}
+}
@@ -0,0 +1,87 @@
+package output
+
+// Format of the Output of a Command
+type Format string
+const (
+	Table	Format	= "table"		// human readable, aligned Columns
+	JSON	Format	= "json"		// JSON Array of Objects
+	YAML	Format	= "yaml"		// YAML List of Objects
+	CSV		Format	= "csv"			// CSV with Header Line
+)
+
+// Record is a single Row of the Output
+type Record interface {
+	Values()	[]string		// Values of the Columns (in the same Order as the Columns)
+}
+
+// ImageRecord describes a single Tag of an Image
+type ImageRecord struct {
+	Registry			string		`json:"registry" yaml:"registry"`
+	Repository			string		`json:"repository" yaml:"repository"`
+	Image				string		`json:"image" yaml:"image"`
+	Tag					string		`json:"tag" yaml:"tag"`
+	Digest				string		`json:"digest" yaml:"digest"`
+	SignatureCount		int			`json:"signatureCount" yaml:"signatureCount"`
+	SignatureBackends	[]string	`json:"signatureBackends" yaml:"signatureBackends"`
+	Aliases				[]string	`json:"aliases" yaml:"aliases"`
+
+	// only in the JSON and YAML Output
+	MediaType			string				`json:"mediaType" yaml:"mediaType"`
+	Platforms			[]PlatformRecord	`json:"platforms,omitempty" yaml:"platforms,omitempty"`
+}
+
+// PlatformRecord describes a Platform Manifest of a Tag which points to an Image Index
+type PlatformRecord struct {
+	Platform			string		`json:"platform" yaml:"platform"`
+	Digest				string		`json:"digest" yaml:"digest"`
+	SignatureCount		int			`json:"signatureCount" yaml:"signatureCount"`
+}
+
+// ManifestRecord describes the Manifest (and Image Configuration) of a single Platform of a Tag
+type ManifestRecord struct {
+	Registry			string		`json:"registry" yaml:"registry"`
+	Image				string		`json:"image" yaml:"image"`
+	Tag					string		`json:"tag" yaml:"tag"`
+	Platform			string		`json:"platform" yaml:"platform"`
+	Digest				string		`json:"digest" yaml:"digest"`
+	Created				string		`json:"created" yaml:"created"`
+	Size				int64		`json:"size" yaml:"size"`
+
+	// only in the JSON and YAML Output
+	MediaType			string				`json:"mediaType" yaml:"mediaType"`
+	Architecture		string				`json:"architecture" yaml:"architecture"`
+	OS					string				`json:"os" yaml:"os"`
+	Layers				[]LayerRecord		`json:"layers" yaml:"layers"`
+	Labels				map[string]string	`json:"labels" yaml:"labels"`
+}
+
+// LayerRecord describes a Layer of a Manifest
+type LayerRecord struct {
+	Digest				string		`json:"digest" yaml:"digest"`
+	MediaType			string		`json:"mediaType" yaml:"mediaType"`
+	Size				int64		`json:"size" yaml:"size"`
+}
+
+// StatsRecord describes the Statistics of a Registry
+type StatsRecord struct {
+	Registry			string		`json:"registry" yaml:"registry"`
+	URI					string		`json:"uri" yaml:"uri"`
+	Repositories		int			`json:"repositories" yaml:"repositories"`
+	Images				int			`json:"images" yaml:"images"`
+	Tags				int			`json:"tags" yaml:"tags"`
+	Signatures			int			`json:"signatures" yaml:"signatures"`
+	NotarySignatures	int			`json:"notarySignatures" yaml:"notarySignatures"`
+}
+
+// Column Names of the Records (Table and CSV Output)
+var (
+	ImageColumns = []string{"REGISTRY", "REPOSITORY", "IMAGE", "TAG", "DIGEST", "SIGNATURES", "BACKENDS", "ALIASES"}
+	ManifestColumns = []string{"REGISTRY", "IMAGE", "TAG", "PLATFORM", "DIGEST", "CREATED", "SIZE", "LAYERS"}
+	StatsColumns = []string{"REGISTRY", "URI", "REPOSITORIES", "IMAGES", "TAGS", "SIGNATURES", "NOTARY"}
+)
+
+// Names of the Signature Backends
+const (
+	BackendSigstore	= "sigstore"	// Signature Store (S3, Directory, Lookaside Web-Server)
+	BackendNotary	= "notary"		// Trust Data on the Notary Server
+)
@@ -0,0 +1,193 @@
+// Prune Package decides (based on a Retention Policy) which
+// Signatures are not needed anymore and deletes them
+package prune
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/config"
+	"github.com/fabmation-gmbh/oima/pkg/registry"
+	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
+	"github.com/fabmation-gmbh/oima/pkg/signature"
+)
+
+// NewPolicy compiles the Retention Policy of the User Configuration
+func NewPolicy(conf config.PruneConf) (*Policy, error) {
+	p := &Policy{ KeepLast: conf.KeepLast }
+
+	for _, v := range conf.KeepTags {
+		expr, err := regexp.Compile(v)
+		if err != nil {
+			Log.Errorf("Invalid Regular Expression '%s' in 'prune.keepTags': %s", v, err.Error())
+			return nil, err
+		}
+
+		p.KeepTags = append(p.KeepTags, expr)
+	}
+
+	if len(conf.MaxAge) > 0 {
+		maxAge, err := time.ParseDuration(conf.MaxAge)
+		if err != nil {
+			Log.Errorf("Invalid Duration '%s' in 'prune.maxAge': %s", conf.MaxAge, err.Error())
+			return nil, err
+		}
+
+		p.MaxAge = maxAge
+	}
+
+	return p, nil
+}
+
+// Plan walks through all Images of the Registry and returns
+// the Decision of the Policy for every Signature
+func (p *Policy) Plan(ctx context.Context, r *registry.DockerRegistry) ([]Action, error) {
+	var actions []Action
+
+	for iRepo := range r.Repos {
+		for iImg := range r.Repos[iRepo].Images {
+			imageActions, err := p.PlanImage(ctx, &r.Repos[iRepo].Images[iImg])
+			if err != nil { return nil, err }
+
+			actions = append(actions, imageActions...)
+		}
+	}
+
+	return actions, nil
+}
+
+// PlanImage returns the Decision of the Policy for every Signature of @img.
+// Signatures belongs to a Digest, so every Signature is only read and decided
+// once (for the first Tag of the Digest), even if several Tags points to it.
+func (p *Policy) PlanImage(ctx context.Context, img *registry.Image) ([]Action, error) {
+	var actions []Action
+	var signedTags []signedTag
+
+	for _, d := range img.GetDigests() {
+		if len(d.Signatures) == 0 { continue }
+
+		tag := findTag(img, d.Tags[0])
+
+		var signedAt time.Time
+		var mismatches []Action
+
+		for _, name := range d.Signatures {
+			data, err := img.ReadSignature(ctx, tag, name)
+			if err != nil {
+				Log.Errorf("Error while downloading Signature '%s' of '%s:%s': %s", name, img.Name, tag.Name, err.Error())
+				return nil, err
+			}
+
+			sig, err := signature.Parse(data)
+			if err != nil {
+				Log.Warningf("Ignoring invalid Signature '%s' of '%s:%s': %s", name, img.Name, tag.Name, err.Error())
+				continue
+			}
+
+			// always drop Signatures which were created for another Digest
+			if sig.ManifestDigest != d.Digest {
+				mismatches = append(mismatches, Action{
+					Image:     img,
+					Tag:       tag,
+					Signature: name,
+					Delete:    true,
+					Reason:    fmt.Sprintf("signed Digest %s does not match the Digest %s", sig.ManifestDigest, d.Digest),
+				})
+				continue
+			}
+
+			if sig.Timestamp.After(signedAt) { signedAt = sig.Timestamp }
+		}
+
+		// the following Signatures are renumbered if a single Signature is deleted (see sigstore.DeleteSignature()),
+		// so the Signatures are deleted from the highest Index down, the kept Signatures are moved down then
+		for iMismatch := len(mismatches) - 1; iMismatch >= 0; iMismatch-- {
+			actions = append(actions, mismatches[iMismatch])
+		}
+
+		// only Tags with (at least one) matching Signature are handled by the Policy,
+		// every Tag of the Digest is ranked on its own (with the Time of the Digest)
+		if len(mismatches) < len(d.Signatures) {
+			for _, name := range d.Tags {
+				signedTags = append(signedTags, signedTag{ tag: findTag(img, name), signedAt: signedAt })
+			}
+		}
+	}
+
+	// newest Tags first
+	sort.SliceStable(signedTags, func(i, j int) bool { return signedTags[i].signedAt.After(signedTags[j].signedAt) })
+
+	// Signatures are only deleted if the Policy drops all Tags which points to the Digest,
+	// so only one Action (of the newest Tag) is returned for every Digest
+	var order []string
+	decisions := make(map[string]Action)
+
+	for i, v := range signedTags {
+		keep, reason := p.decide(v, i)
+
+		decision, ok := decisions[v.tag.ContentDigest]
+		switch {
+		case !ok:
+			order = append(order, v.tag.ContentDigest)
+			decision = Action{ Image: img, Tag: v.tag, Signature: rt.AllSignatures, Delete: !keep, Reason: reason }
+		case keep && decision.Delete:
+			decision.Delete = false
+			decision.Reason = fmt.Sprintf("Digest is shared with kept Tag '%s'", v.tag.Name)
+		}
+
+		decisions[v.tag.ContentDigest] = decision
+	}
+
+	for _, v := range order { actions = append(actions, decisions[v]) }
+
+	return actions, nil
+}
+
+// decide returns if the Tag @v (the @rank'th newest signed Tag) should be kept
+func (p *Policy) decide(v signedTag, rank int) (bool, string) {
+	for _, expr := range p.KeepTags {
+		if expr.MatchString(string(v.tag.Name)) { return true, fmt.Sprintf("Tag matches '%s'", expr.String()) }
+	}
+
+	if rank < p.KeepLast { return true, fmt.Sprintf("one of the newest %d Tags", p.KeepLast) }
+
+	if p.MaxAge > 0 {
+		if v.signedAt.IsZero() { return true, "Signature has no Timestamp" }
+
+		if time.Since(v.signedAt) > p.MaxAge {
+			return false, fmt.Sprintf("signed %s ago (older than %s)", time.Since(v.signedAt).Round(time.Hour), p.MaxAge)
+		}
+
+		return true, fmt.Sprintf("younger than %s", p.MaxAge)
+	}
+
+	if p.KeepLast > 0 { return false, fmt.Sprintf("not one of the newest %d Tags", p.KeepLast) }
+
+	return true, "no Rule matches"
+}
+
+// findTag returns the Tag @name of @img
+func findTag(img *registry.Image, name rt.TagName) *rt.Tag {
+	for iTag := range img.Tags {
+		if img.Tags[iTag].Name == name { return &img.Tags[iTag] }
+	}
+
+	return nil
+}
+
+// Apply executes all Actions which deletes a Signature
+// and stops at the first Error
+func Apply(ctx context.Context, actions []Action) error {
+	for _, v := range actions {
+		if !v.Delete { continue }
+
+		err := v.Image.DeleteSignature(ctx, v.Tag, v.Signature)
+		if err != nil { return err }
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package prune
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/packet"
+
+	"github.com/fabmation-gmbh/oima/internal"
+	"github.com/fabmation-gmbh/oima/pkg/config"
+	"github.com/fabmation-gmbh/oima/pkg/credential"
+	"github.com/fabmation-gmbh/oima/pkg/registry"
+	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
+	"github.com/fabmation-gmbh/oima/pkg/signature"
+	"github.com/fabmation-gmbh/oima/pkg/sigstore"
+)
+
+const (
+	shared		= "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	kept		= "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	unknown		= "sha256:3333333333333333333333333333333333333333333333333333333333333333"
+)
+
+// testSignature is a Signature of the Manifest @digest in the Signature Store,
+// which was created for the Manifest @signed
+type testSignature struct{ digest, name, signed string }
+
+// newTestRegistry returns a Registry with the Image 'team/app', whose Tags '1.0' and 'latest' point to
+// the Digest @shared and the Tag 'old' to the Digest @kept, and a File Signature Store with @signatures
+func newTestRegistry(t *testing.T, signatures []testSignature) (*registry.DockerRegistry, *sigstore.FileStore, string) {
+	digests := map[string]string{ "1.0": shared, "latest": shared, "old": kept }
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/":
+			w.WriteHeader(200)
+		case r.URL.Path == "/v2/_catalog":
+			fmt.Fprint(w, `{"repositories":["team/app"]}`)
+		case r.URL.Path == "/v2/team/app/tags/list":
+			fmt.Fprint(w, `{"name":"team/app","tags":["1.0","latest","old"]}`)
+		case strings.HasPrefix(r.URL.Path, "/v2/team/app/manifests/"):
+			w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+			w.Header().Set("Docker-Content-Digest", digests[strings.TrimPrefix(r.URL.Path, "/v2/team/app/manifests/")])
+			fmt.Fprint(w, `{"schemaVersion":2,"mediaType":"application/vnd.docker.distribution.manifest.v2+json"}`)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	store := &sigstore.FileStore{ Dir: dir }
+	image := sigstore.ImagePath(server.URL, "team/app")
+
+	signer, err := openpgp.NewEntity("oima", "", "oima@example.com", &packet.Config{ DefaultHash: crypto.SHA256 })
+	if err != nil { t.Fatal(err) }
+
+	for _, v := range signatures {
+		payload, err := signature.NewPayload(image, v.signed, "oima")
+		if err != nil { t.Fatal(err) }
+
+		data, err := signature.Sign(payload, signer)
+		if err != nil { t.Fatal(err) }
+
+		err = store.Write(context.Background(), image, v.digest, v.name, data)
+		if err != nil { t.Fatal(err) }
+	}
+
+	viper.Set("registry", map[string]interface{}{ "uri": server.URL, "sigstore": "file://" + dir })
+	internal.Cred = new(credential.CredStore)
+
+	var r registry.DockerRegistry
+	if err := r.Init(context.Background()); err != nil { t.Fatal(err) }
+	if err := r.FetchAll(context.Background()); err != nil { t.Fatal(err) }
+
+	return &r, store, image
+}
+
+// plan returns the Plan of @policy for @r (one Line per Action)
+func plan(t *testing.T, policy *Policy, r *registry.DockerRegistry) ([]Action, string) {
+	actions, err := policy.Plan(context.Background(), r)
+	if err != nil { t.Fatal(err) }
+
+	var got []string
+	for _, v := range actions {
+		got = append(got, fmt.Sprintf("%s %s %t", v.Tag.ContentDigest, v.Signature, v.Delete))
+	}
+
+	return actions, strings.Join(got, "\n")
+}
+
+// TestPlanAliases checks that the Signatures of a Digest, which is shared by
+// the Tags '1.0' and 'latest', are planned and deleted only once (File Signature Store)
+func TestPlanAliases(t *testing.T) {
+	// the Signature Store contains a valid Signature of both Digests and
+	// a Signature of the shared Digest which was created for another Digest
+	r, store, image := newTestRegistry(t, []testSignature{
+		{ shared, sigstore.SignatureName(1), shared },
+		{ shared, sigstore.SignatureName(2), unknown },
+		{ kept, sigstore.SignatureName(1), kept },
+	})
+
+	// drops all Signatures, except the ones of 'old'
+	policy, err := NewPolicy(config.PruneConf{ KeepTags: []string{"^old$"}, MaxAge: "1ns" })
+	if err != nil { t.Fatal(err) }
+
+	actions, got := plan(t, policy, r)
+
+	expected := strings.Join([]string{
+		fmt.Sprintf("%s %s true", shared, sigstore.SignatureName(2)),
+		fmt.Sprintf("%s %s true", shared, rt.AllSignatures),
+		fmt.Sprintf("%s %s false", kept, rt.AllSignatures),
+	}, "\n")
+	if got != expected { t.Fatalf("unexpected Plan:\n%s\nexpected:\n%s", got, expected) }
+
+	if err := Apply(context.Background(), actions); err != nil { t.Fatal(err) }
+
+	for digest, count := range map[string]int{ shared: 0, kept: 1 } {
+		names, err := store.List(context.Background(), image, digest)
+		if err != nil { t.Fatal(err) }
+
+		if len(names) != count { t.Errorf("expected %d Signatures of %s, got %v", count, digest, names) }
+	}
+}
+
+// TestPlanMismatchKept checks that the Signatures of a kept Digest, which were created for
+// another Digest (even the Digest of another Tag), are deleted without hiding the valid Signature
+func TestPlanMismatchKept(t *testing.T) {
+	r, store, image := newTestRegistry(t, []testSignature{
+		{ shared, sigstore.SignatureName(1), shared },
+		{ kept, sigstore.SignatureName(1), shared },
+		{ kept, sigstore.SignatureName(2), kept },
+		{ kept, sigstore.SignatureName(3), unknown },
+	})
+
+	policy, err := NewPolicy(config.PruneConf{ KeepTags: []string{"^old$", "^latest$"} })
+	if err != nil { t.Fatal(err) }
+
+	actions, got := plan(t, policy, r)
+
+	// the Signatures are deleted from the highest Index down
+	expected := strings.Join([]string{
+		fmt.Sprintf("%s %s true", kept, sigstore.SignatureName(3)),
+		fmt.Sprintf("%s %s true", kept, sigstore.SignatureName(1)),
+		fmt.Sprintf("%s %s false", shared, rt.AllSignatures),
+		fmt.Sprintf("%s %s false", kept, rt.AllSignatures),
+	}, "\n")
+	if got != expected { t.Fatalf("unexpected Plan:\n%s\nexpected:\n%s", got, expected) }
+
+	if err := Apply(context.Background(), actions); err != nil { t.Fatal(err) }
+
+	// the valid Signature is moved to 'signature-1', so Consumers still find it
+	names, err := store.List(context.Background(), image, kept)
+	if err != nil { t.Fatal(err) }
+	if len(names) != 1 || names[0] != sigstore.SignatureName(1) { t.Fatalf("expected only %s, got %v", sigstore.SignatureName(1), names) }
+
+	data, err := store.Read(context.Background(), image, kept, sigstore.SignatureName(1))
+	if err != nil { t.Fatal(err) }
+
+	sig, err := signature.Parse(data)
+	if err != nil { t.Fatal(err) }
+	if sig.ManifestDigest != kept { t.Errorf("expected the Signature of %s, got the Signature of %s", kept, sig.ManifestDigest) }
+}
@@ -0,0 +1,32 @@
+package prune
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/fabmation-gmbh/oima/pkg/registry"
+	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
+)
+
+// Policy describes which Signatures should be kept
+type Policy struct {
+	KeepLast		int					// Keep the newest N signed Tags of every Image
+	KeepTags		[]*regexp.Regexp	// Keep all Tags matching one of the Expressions
+	MaxAge			time.Duration		// Drop Tags signed before this Duration (0 = disabled)
+}
+
+// Action describes the Decision of the Policy for a single Signature
+// (or for all Signatures of a Tag if Signature is rt.AllSignatures)
+type Action struct {
+	Image			*registry.Image		// Image of the Tag
+	Tag				*rt.Tag				// Tag the Signature belongs to
+	Signature		string				// Name of the Signature (or rt.AllSignatures)
+	Delete			bool				// Should the Signature be deleted
+	Reason			string				// Human readable Reason of the Decision
+}
+
+// signedTag is a Tag with the Time it was signed (newest Signature)
+type signedTag struct {
+	tag				*rt.Tag
+	signedAt		time.Time
+}
@@ -2,23 +2,23 @@
 package registry
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"github.com/awnumar/memguard"
-	"github.com/fabmation-gmbh/oima/pkg/s3"
-	"os"
+	"github.com/fabmation-gmbh/oima/pkg/notary"
+	"github.com/fabmation-gmbh/oima/pkg/sigstore"
+	nethttp "net/http"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
-	"time"
-
-	"github.com/go-resty/resty"
+	"sync/atomic"
 
 	"github.com/fabmation-gmbh/oima/internal"
 	. "github.com/fabmation-gmbh/oima/internal/log"
 	"github.com/fabmation-gmbh/oima/pkg/config"
 	"github.com/fabmation-gmbh/oima/pkg/errors"
+	"github.com/fabmation-gmbh/oima/pkg/http"
 	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
 )
 
@@ -38,7 +38,8 @@ const (
 type Auth struct {
 	dockerRegistry	*DockerRegistry		// Pointer to Parent Struct
 
-	Required		bool				// Is Authentication Required
+	Required		bool				// Is Authentication Required (Credentials are configured)
+	Mode			_AuthMode			// How Requests are authenticated (none, basic, bearer)
 	Cred			Credential			// Needed Credentials
 }
 
@@ -58,19 +59,39 @@ type Credential struct {
 
 	Username		string				// Username
 	Password		*memguard.Enclave	// Password stored securely
-	Token			BearerToken			// The BearerToken is needed to communicate with the Registry API stored securely
+
+	challenge		*authChallenge			// Challenge sent by the Registry on '/v2/' (nil if none)
+	tokenFlow		_TokenFlow				// How Bearer Tokens are requested
+	tokens			map[string]*scopeToken	// Bearer Tokens (stored securely) cached per Scope
+	tokenLock		sync.Mutex				// Protects @tokens (but not the Tokens itself)
+}
+
+// scopeToken is the cached Bearer Token of a single Scope
+type scopeToken struct {
+	lock			sync.Mutex			// Held while the Token of the Scope is requested
+	token			*BearerToken		// current Token (nil if not requested yet)
 }
 
 // Holds all Informations that are needed to talk with the Registry API
 // Implements the @registry Interface
 type DockerRegistry struct {
+	Name			string				// Name of the Registry in the Configuration (empty selects the first Registry)
 	Version			_RegistryVersion	// API Version
 	URI				string				// Registry URI
 	Authentication	Auth				// Authentication Informations and Credentials
 	Repos			[]Repository		// List of all Repos in the Registry
+	PageSize		int					// Number of Entries requested per Page ('_catalog', 'tags/list')
+	Concurrency		int					// Max. Number of concurrent Requests (Registry and Signature Store)
 
-	s3Cli			s3.S3				// S3 Object to check and edit Signatures
-	s3Enabled		bool				// Check if S3 Server is disabled by user
+	regConf			config.RegistryConf	// Configuration of this Registry
+	sched			*scheduler			// Limits the concurrent Requests to @Concurrency
+	httpClient		*nethttp.Client		// Shared by all Registry and Token Server Requests
+
+	sigStore		sigstore.SignatureStore	// Signature Store to check and edit Signatures
+	sigStoreEnabled	bool				// Check if a Signature Store is configured by user
+
+	notaryCli		notary.Notary		// Notary Object to check and edit Trust Data
+	notaryEnabled	bool				// Check if Notary Server is disabled by user
 }
 
 // A (Docker) Repository is (for example) the 'atlassian-jira' in 'docker.reg.local/atlassian-jira:v1.0.0'
@@ -114,174 +135,177 @@ type Image struct {
 /// >>>>>>>>>> Functions <<<<<<<<<< ///
 
 
+// Init initializes the Registry, the Signature Store, Notary and the Authentication.
+// Errors are returned to the Caller, nothing is exiting the Process.
 //noinspection GoNilness
-func (r *DockerRegistry) Init() error {
+func (r *DockerRegistry) Init(ctx context.Context) error {
 	conf = internal.GetConfig()
 
+	// get Configuration of the selected Registry
+	regConf, ok := conf.GetRegistry(r.Name)
+	if !ok {
+		Log.Errorf("Registry '%s' is not configured", r.Name)
+		return errors.NewRegistryNotFoundError(r.Name)
+	}
+	r.regConf = regConf
+	r.Name = regConf.Name
+
 	// set Flags
-	r.URI = conf.Registry.RegistryURI
+	r.URI = r.regConf.RegistryURI
 	Log.Debugf("Set DockerRegistry URI: %s", r.URI)
 
+	r.PageSize = r.regConf.PageSize
+	if r.PageSize <= 0 { r.PageSize = defaultPageSize }
+
+	r.Concurrency = r.regConf.Concurrency
+	if r.Concurrency <= 0 { r.Concurrency = defaultConcurrency }
+	r.sched = newScheduler(r.Concurrency)
+
+	// TLS, Proxy and Timeouts of the Registry (and Token Server)
+	httpClient, err := http.NewClient(r.regConf.Transport)
+	if err != nil {
+		Log.Errorf("Invalid 'transport' Configuration of Registry '%s': %s", r.Name, err.Error())
+		return err
+	}
+	r.httpClient = httpClient
+
 	// set parent Pointer back to this struct
 	r.Authentication.dockerRegistry = r
 
-	// initialize S3 Server
-	if conf.S3.Enabled {
-		Log.Debugf("Initializing S3 Object")
-		r.s3Enabled = true
-		r.s3Cli = &s3.S3Minio{}
+	// initialize Signature Store
+	if storeConf := conf.GetSigstore(r.regConf); len(storeConf.URI) > 0 {
+		Log.Debugf("Initializing Signature Store '%s' (%s)", storeConf.Name, storeConf.URI)
+
+		store, err := newSignatureStore(storeConf)
+		if err != nil {
+			Log.Errorf("Error while creating Signature Store: %s", err.Error())
+			return err
+		}
 
-		err := r.s3Cli.InitS3()
+		err = store.Init(ctx)
 		if err != nil {
-			Log.Fatalf("Error while Initializing S3 Struct: %s", err.Error())
+			Log.Errorf("Error while Initializing Signature Store: %s", err.Error())
 			return err
-		} else { Log.Notice("S3 Struct initialized successfully") }
+		} else { Log.Debug("Signature Store initialized successfully") }
 
+		r.sigStore = store
+		r.sigStoreEnabled = true
 	} else {
-		Log.Debugf("S3 Server Component was disabled by User Conf.")
+		Log.Debugf("No Signature Store configured by User Conf.")
 	}
 
-	// Initialize Auth Struct
-	b, _ := strconv.ParseBool(conf.Registry.RequireAuth)
-	r.Authentication.Required = b
-
-	r.Authentication.Init()
+	// initialize Notary Server
+	if conf.Notary.Enabled {
+		Log.Debugf("Initializing Notary Object")
+		r.notaryEnabled = true
+		r.notaryCli = &notary.NotaryServer{}
 
-	err := r.Authentication.Cred.Init()
-	if err != nil {
-		Log.PanicF("Could not Initialize Credentials: %s", err.Error())
-	}
+		err := r.notaryCli.InitNotary()
+		if err != nil {
+			Log.Errorf("Error while Initializing Notary Struct: %s", err.Error())
+			return err
+		} else { Log.Debug("Notary Struct initialized successfully") }
 
-	// add Repo '/', this is a pseudonym for all Images
-	// which are stored at the Root Path (this is possible in e.g. JFrog Artifactory)
-	var doBreak bool
-	repo := Repository{
-		DockerRegistry: r,
-		Name:           "/",
-		Images:         nil,
+	} else {
+		Log.Debugf("Notary Server Component was disabled by User Conf.")
 	}
 
-	// check if this Pseudonym already exists, because
-	// the Application could call Init() twice (or more)
-	for _, repoV := range r.Repos { if repoV.Name == "" { doBreak = true; break } }
-	if doBreak { doBreak = false }
-
+	// Initialize Auth Struct
+	b, _ := strconv.ParseBool(r.regConf.RequireAuth)
+	r.Authentication.Required = b
 
-	// fetch Images from '/'
-	Log.Debugf("Fetching Images for Root of Registry '%s", r.URI)
+	r.Authentication.Init()
 
-	err = repo.FetchAllImages()
+	err = r.Authentication.Cred.Init(ctx)
 	if err != nil {
-		Log.Fatalf("Error while Fetching all Images: %s", err.Error())
+		Log.Errorf("Could not Initialize Credentials: %s", err.Error())
 		return err
 	}
-	r.Repos = append(r.Repos, repo)
 
 	return nil
 }
 
+// ListRepositories returns all Repositories of the Registry
+// (they are fetched if they were not fetched before)
 //noinspection GoNilness
-func (r *DockerRegistry) ListRepositories() []Repository {
+func (r *DockerRegistry) ListRepositories(ctx context.Context) ([]Repository, error) {
 	// check if Repos where already fetched
 	if len(r.Repos) == 0 || r.Repos == nil {
 		// fetch all Informations
-		err := r.FetchAll()
+		err := r.FetchAll(ctx)
 		if err != nil {
-			Log.Fatalf("Error while Fetching all Informations from Registry '%s': %s", r.URI, err.Error())
-			os.Exit(1)
+			Log.Errorf("Error while Fetching all Informations from Registry '%s': %s", r.URI, err.Error())
+			return nil, err
 		}
 	}
 
-	return r.Repos
+	return r.Repos, nil
 }
 
-func (r *DockerRegistry) FetchAll() error {
-	authData := authInfo{
-		token:   nil,
-		authReq: r.Authentication.Required,
-	}
+// FetchAll fetches all Repositories (with Images, Tags and Signatures) of the Registry.
+// The Requests are sent concurrently (see DockerRegistry.Concurrency), the first
+// Error cancels all other Requests.
+func (r *DockerRegistry) FetchAll(ctx context.Context) error {
+	catalog, err := r.fetchCatalog(ctx)
+	if err != nil { return err }
 
-	if r.Authentication.Required {
-		// check if the Bearer Token is expired, and renew it if needed
-		if r.Authentication.Cred.Token.ExpiresOn <= time.Now().Unix() {
-			// renew BearerToken
-			Log.Debugf("Re-Newing BearerToken because it's expired on %s", time.Unix(r.Authentication.Cred.Token.ExpiresOn, 0))
+	// add Repo '/', this is a pseudonym for all Images
+	// which are stored at the Root Path (this is possible in e.g. JFrog Artifactory)
+	// check if this Pseudonym already exists, because
+	// the Application could call FetchAll() twice (or more)
+	existing := make(map[string]bool)
+	for _, repoV := range r.Repos { existing[repoV.Name] = true }
+
+	var repoNames []string
+	if !existing["/"] {
+		existing["/"] = true
+		repoNames = append(repoNames, "/")
+	}
 
-			err := r.Authentication.Cred.getBearerToken()
-			if err != nil {
-				Log.Fatalf("Error while re-newing the BearerToken: %s", err.Error())
-				return err
-			}
-		}
+	// collect the Names of the (new) Repos, every Repo is only fetched once
+	// Example: the Entry 'testing/unstable/jira' belongs to the Repo 'testing/unstable'
+	for _, val := range catalog {
+		// if Entry does not contain a '/' it means that it is a Image
+		if !strings.Contains(val, "/") { continue }
 
-		token, err := r.Authentication.Cred.Token.BearerToken.Open()
-		if err != nil {
-			memguard.SafePanic(err)
-		}
-		defer token.Destroy()
+		name := repositoryName(val)
+		if existing[name] { continue }
 
-		authData.token = token
+		existing[name] = true
+		repoNames = append(repoNames, name)
 	}
 
-	// get Registry Catalog
-	catalog, err := getRegistryCatalog(&authData, r.URI, r.Version)
-	if err != nil {
-		Log.Fatalf("Error while fetching Registry Catalog: %s", err.Error())
-		memguard.SafeExit(1)
+	// allocate all Repositories first, so every Task can work on its own Entry
+	start := len(r.Repos)
+	for _, name := range repoNames {
+		r.Repos = append(r.Repos, Repository{
+			DockerRegistry: r,
+			Name:           name,
+			Images:         nil,
+		})
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(len(catalog))
+	group, groupCtx := newFetchGroup(ctx, r.Concurrency)
 
-	var retErr error
-	retErr = nil
+	for iRepo := start; iRepo < len(r.Repos); iRepo++ {
+		repo := &r.Repos[iRepo]
 
-	for _, val := range catalog {
-		go func(val string) {
-			defer wg.Done()
-
-			// if Entry does not contain a '/' it means that it is a Image
-			if strings.Contains(val, "/") {
-				repoName := strings.Split(val, "/")
-				lenRepos := len(repoName)
-				var name string
-				for i, v := range repoName {
-					if i == (lenRepos - 1) { break }
-
-					// prevent adding a Slash to the last Repo Entry Name
-					if i != (lenRepos -2 ) {
-						name += fmt.Sprintf("%s/", v)
-					} else { name += v }
-				}
-
-				repo := Repository{
-					DockerRegistry: r,
-					Name:           name,
-					Images:         nil,
-				}
-
-				// check if a Repo already exists with that Name
-				for _, repoV := range r.Repos { if repoV.Name == name { return } }
-
-				// fetch Images from repo
-				Log.Debugf("Fetching Images for Repo '%s'", repo.Name)
-
-				err := repo.FetchAllImages()
-				if err != nil {
-					Log.Fatalf("Error while Fetching all Images: %s", err.Error())
-					retErr = err
-					wg.Done()
-					return
-				}
+		group.do(func() error {
+			// fetch Images from repo
+			Log.Debugf("Fetching Images for Repo '%s'", repo.Name)
 
-				r.Repos = append(r.Repos, repo)
-				Log.Debugf("-- New Repo Entry: %s", repo.Name)
+			err := repo.fetchImages(groupCtx, catalog)
+			if err != nil {
+				Log.Errorf("Error while Fetching all Images of Repo '%s': %s", repo.Name, err.Error())
+				return err
 			}
-		}(val)
+
+			Log.Debugf("-- New Repo Entry: %s", repo.Name)
+			return nil
+		})
 	}
 
-	// wait on for-loop
-	wg.Wait()
+	err = group.wait()
 
 	// TODO: (1) Test Time with a Lot of Repos/ Images/ Tags
 	// TODO: (2) Implement own sort Algorithm to improve runtime?
@@ -289,18 +313,48 @@ func (r *DockerRegistry) FetchAll() error {
 	// TODO:      and this functions adds the Repo at the optimal place
 	// sort Repos (this increases the runtime about +6,4281014 %)
 	sort.Slice(r.Repos, func(i, j int) bool { return r.Repos[i].Name < r.Repos[j].Name 	})
+	r.relink()
 
-	if retErr != nil { return retErr }
+	return err
+}
 
-	return nil
+// fetchCatalog returns the Catalog (all Image Names) of the Registry
+func (r *DockerRegistry) fetchCatalog(ctx context.Context) ([]string, error) {
+	// get (or renew) the Bearer Token
+	authData, err := r.authData(ctx, catalogScope)
+	if err != nil {
+		Log.Errorf("Error while getting the BearerToken: %s", err.Error())
+		return nil, err
+	}
+	defer authData.destroy()
+
+	var catalog []string
+	err = r.sched.do(ctx, func() error {
+		catalog, err = getRegistryCatalog(ctx, authData, r.URI, r.Version, r.PageSize)
+		return err
+	})
+	if err != nil {
+		Log.Errorf("Error while fetching Registry Catalog: %s", err.Error())
+		return nil, err
+	}
+
+	return catalog, nil
 }
 
-func (r *DockerRegistry) Stats() rt.Stats {
-	// Update Registry Data (Repos, Images, ...)
-	err := r.FetchAll()
-	if err != nil { Log.Panicf("Error while Fetching Repository Data") }
+// relink sets the Parent Pointers of all Repositories and Images,
+// they must be updated after the Slices were sorted or re-allocated
+func (r *DockerRegistry) relink() {
+	for iRepo := range r.Repos {
+		repo := &r.Repos[iRepo]
+		repo.DockerRegistry = r
+
+		for iImg := range repo.Images { repo.Images[iImg].Repository = repo }
+	}
+}
 
-	var images, tags, s3signatures int = 0, 0, 0
+// Stats returns the Statistics of the (already fetched) Repositories
+func (r *DockerRegistry) Stats() rt.Stats {
+	var images, tags, s3signatures, notarySignatures int = 0, 0, 0, 0
 
 	for _, repo := range r.Repos {
 		// count Images
@@ -310,7 +364,8 @@ func (r *DockerRegistry) Stats() rt.Stats {
 			// count Tags
 			for _, tag := range img.Tags {
 				tags++
-				if tag.S3SignFound { s3signatures++ }
+				s3signatures += tag.SignatureCount()
+				if tag.NotarySignFound { notarySignatures++ }
 			}
 		}
 	}
@@ -320,31 +375,36 @@ func (r *DockerRegistry) Stats() rt.Stats {
 		Images:       images,
 		Tags:         tags,
 		S3Signatures: s3signatures,
+		NotarySignatures: notarySignatures,
 	}
 }
 
 
-func (r *Repository) ListImages() ([]Image, error) {
+// ListImages returns all Images (with Tags) of the Repository
+// (they are fetched if they were not fetched before)
+func (r *Repository) ListImages(ctx context.Context) ([]Image, error) {
 	if len(r.Name) == 0 {
-		Log.Fatal("[Internal Error] Trying to List Images about a Repo which Name is not set!!")
+		Log.Error("[Internal Error] Trying to List Images about a Repo which Name is not set!!")
 		return nil, errors.NewRepositoryNameNotDefinedError()
 	}
 
 	if r.Images == nil {
-		err := r.FetchAllImages()
+		err := r.FetchAllImages(ctx)
 		if err != nil {
-			Log.Fatalf("Error while fetching all Images from Repo '%s': %s", r.Name, err.Error())
-			memguard.SafeExit(1)
+			Log.Errorf("Error while fetching all Images from Repo '%s': %s", r.Name, err.Error())
+			return nil, err
 		}
 	}
 
 	// fetch all Images (and Image Tags)
-	for _, v := range r.Images {
+	for iImg := range r.Images {
+		v := &r.Images[iImg]
+
 		// fetch Tags if len of Tags are 0
 		if v.Tags == nil || len(v.Tags) == 0 {
-			err := v.FetchAllTags()
+			err := v.FetchAllTags(ctx)
 			if err != nil {
-				Log.Fatalf("Error while Fetching Image Tags: %s", err.Error())
+				Log.Errorf("Error while Fetching Image Tags: %s", err.Error())
 				return nil, err
 			}
 		}
@@ -353,194 +413,283 @@ func (r *Repository) ListImages() ([]Image, error) {
 	return r.Images, nil
 }
 
-func (r *Repository) FetchAllImages() error {
+// FetchAllImages fetches all Images (with Tags and Signatures) of the Repository
+func (r *Repository) FetchAllImages(ctx context.Context) error {
 	if len(r.Name) == 0 {
-		Log.Fatal("[Internal Error] Trying to fetch all Images in a Repo which Name is not set!!")
+		Log.Error("[Internal Error] Trying to fetch all Images in a Repo which Name is not set!!")
 		return errors.NewRepositoryNameNotDefinedError()
 	}
 
-	var authData = authInfo{
-		token:   nil,
-		authReq: r.DockerRegistry.Authentication.Required,
+	catalog, err := r.DockerRegistry.fetchCatalog(ctx)
+	if err != nil { return err }
+
+	return r.fetchImages(ctx, catalog)
+}
+
+// fetchImages fetches the Images of the Catalog @catalog which belong to the Repository
+func (r *Repository) fetchImages(ctx context.Context, catalog []string) error {
+	if r.Images == nil { r.Images = []Image{} }
+
+	var lock sync.Mutex			// Protects r.Images
+	group, groupCtx := newFetchGroup(ctx, r.DockerRegistry.Concurrency)
+
+	for _, v := range catalog {
+		// check if Entry is an Image of this Repo
+		// (eg 'nextcloud' is a Entry of the Sub-Repo 'library' in 'docker.io/library/nextcloud')
+		if strings.HasSuffix(v, "/") || repositoryName(v) != r.Name { continue }
+
+		name := v
+
+		group.do(func() error {
+			newImage := Image{
+				Repository: r,
+				Name:       name,
+				Tags:       nil,
+			}
+
+			// fetch Image Tags
+			err := newImage.FetchAllTags(groupCtx)
+
+			// check Signatures and Trust Data
+			if err == nil { err = newImage.fetchSignatureData(groupCtx) }
+
+			if err != nil {
+				Log.Errorf("Error while Fetching Tags of Image '%s': %s", newImage.Name, err.Error())
+				return err
+			}
+
+			lock.Lock()
+			r.Images = append(r.Images, newImage)
+			lock.Unlock()
+
+			Log.Debugf("--> Add new Image: %s", newImage.Name)
+			return nil
+		})
 	}
 
-	if r.DockerRegistry.Authentication.Required {
-		token, err := r.DockerRegistry.Authentication.Cred.Token.BearerToken.Open()
-		if err != nil {
-			memguard.SafePanic(err)
-		}
-		defer token.Destroy()
+	// INFO: The Images are not sorted because it increases the runtime about +94 %
+	//		 And sorted Images aren't really important
+	return group.wait()
+}
+
+// FetchFiltered fetches only the Repositories, Images and Tags which match @filter.
+// The Catalog is fetched once, Tags (and their Digests and Signatures) are only
+// fetched for matching Images. Replaces the already fetched Repositories.
+// The Images are fetched in the Order of their Names, so no further Images are
+// fetched once @filter.Limit matching Tags are found.
+func (r *DockerRegistry) FetchFiltered(ctx context.Context, filter *Filter) error {
+	catalog, err := r.fetchCatalog(ctx)
+	if err != nil { return err }
+
+	// group the matching Images by their Repository
+	var repoNames []string
+	var matched int
+	repoImages := make(map[string][]string)
 
-		authData.token = token
+	for _, name := range catalog {
+		if strings.HasSuffix(name, "/") || !filter.MatchImage(name) { continue }
+
+		repoName := repositoryName(name)
+		if _, ok := repoImages[repoName]; !ok { repoNames = append(repoNames, repoName) }
+
+		repoImages[repoName] = append(repoImages[repoName], name)
+		matched++
 	}
 
-	// get Registry Catalog
-	catalog, err := getRegistryCatalog(&authData, r.DockerRegistry.URI, r.DockerRegistry.Version)
-	if err != nil {
-		Log.Fatalf("Error while fetching Registry Catalog: %s", err.Error())
-		memguard.SafeExit(1)
+	sort.Strings(repoNames)
+	Log.Debugf("%d of %d Catalog Entries match the Filter", matched, len(catalog))
+
+	// allocate all Repositories first, so the Pointers of the Images stay valid
+	r.Repos = make([]Repository, len(repoNames))
+	for iRepo, name := range repoNames {
+		r.Repos[iRepo] = Repository{
+			DockerRegistry: r,
+			Name:           name,
+			Images:         []Image{},
+		}
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(len(catalog))
+	var lock sync.Mutex			// Protects the Images of all Repositories
+	var found int64				// Number of matching Tags (see @filter.Limit)
+	group, groupCtx := newFetchGroup(ctx, r.Concurrency)
+
+fetch:
+	for iRepo := range r.Repos {
+		repo := &r.Repos[iRepo]
+
+		sort.Strings(repoImages[repo.Name])
+		for _, v := range repoImages[repo.Name] {
+			// do() returns as soon as a Worker accepted the Task, so up to @r.Concurrency
+			// further Images may be fetched until @found contains the Tags of all previous Images
+			// (the Result is cut to exactly @filter.Limit Tags after sorting)
+			if filter.Limit > 0 && atomic.LoadInt64(&found) >= int64(filter.Limit) {
+				Log.Debugf("Found %d Tags, stop fetching further Images", filter.Limit)
+				break fetch
+			}
+
+			name := v
 
-	for _, v := range catalog {
-		go func(v string) {
-			defer wg.Done()
-
-			if r.Images == nil { r.Images = []Image{} }
-			// check if Entry is an Image or an Repo
-			if (strings.Contains(v, r.Name) || r.Name == "/") && !strings.HasSuffix(v, "/") {
-				// check if Repo is Root of the Registry
-				if r.Name != "/"{
-					// check if Image is Entry of an Sub-Repo
-					// (eg 'nextcloud' is a Entry of the Sub-Repo 'library' in 'docker.io/library/nextcloud')
-					if strings.Count(v, "/") > (strings.Count(r.Name, "/") + 1) { return }
-				} else { if strings.Count(v, "/") > 0 { return } }
-
-				newImage := Image{
-					Repository: r,
-					Name:       v,
+			group.do(func() error {
+				img := Image{
+					Repository: repo,
+					Name:       name,
 					Tags:       nil,
 				}
 
-				// fetch Image Tags
-				err := newImage.FetchAllTags()
+				err := img.fetchTags(groupCtx, filter)
+				if err == nil { err = img.fetchSignatureData(groupCtx) }
+
 				if err != nil {
-					Log.Fatalf("Error while Fetching Tags of Image '%s': %s", newImage.Name, err.Error())
-					memguard.SafeExit(1)
+					Log.Errorf("Error while Fetching Image '%s': %s", name, err.Error())
+					return err
 				}
 
-				// check Signatures on S3 if S3 is enabled
-				if r.DockerRegistry.s3Enabled {
-					var img rt.BaseImage = &newImage
-					err = r.DockerRegistry.s3Cli.FetchSignatures(&img)
-					if err != nil {
-						Log.Fatalf("Error while Fetching Signatures for Image %s on S3 Server", newImage.Name)
-						memguard.SafeExit(1)
-					}
-				} else {
-					Log.Warningf("S3-Server Component was disabled by User Config")
-				}
+				// drop the Tags (and Images) which do not match
+				tags := img.Tags[:0]
+				for _, tag := range img.Tags { if filter.MatchTag(tag) { tags = append(tags, tag) } }
+				img.Tags = tags
 
-				r.Images = append(r.Images, newImage)
-				Log.Debugf("--> Add new Image: %s", newImage.Name)
-			}
-		}(v)
+				if len(img.Tags) == 0 && filter.tagFilterActive() { return nil }
+
+				atomic.AddInt64(&found, int64(len(img.Tags)))
+
+				lock.Lock()
+				repo.Images = append(repo.Images, img)
+				lock.Unlock()
+
+				return nil
+			})
+		}
 	}
 
-	// wait for For-Loop
-	wg.Wait()
+	err = group.wait()
+	if err != nil { return err }
 
-	// INFO: This is commented out because it increases the runtime about +94 %
-	//		 And sorted Images aren't really important
-	//// TODO: (1) Test Time with a Lot of Repos/ Images/ Tags
-	//// TODO: (2) Implement own sort Algorithm to improve runtime?
-	//// TODO:   => (eg) by calling a Repository.AddImage(...) Function
-	//// TODO:      and this functions adds the Repo at the optimal place
-	//// sort Images
-	//sort.Slice(r.Images, func(i, j int) bool { return r.Images[i].Name < r.Images[j].Name 	})
+	// drop Repositories without matching Images
+	repos := r.Repos[:0]
+	for _, repo := range r.Repos { if len(repo.Images) > 0 { repos = append(repos, repo) } }
+	r.Repos = repos
+
+	for iRepo := range r.Repos {
+		images := r.Repos[iRepo].Images
+		sort.Slice(images, func(i, j int) bool { return images[i].Name < images[j].Name })
+	}
+
+	// all Images before the last fetched one are complete, so the first
+	// @filter.Limit Tags (sorted) are the same on every Run
+	if filter.Limit > 0 { r.limitTags(filter.Limit) }
+	r.relink()
 
 	return nil
 }
 
-//noinspection ALL
-func (i *Image) FetchAllTags() error {
-	Log.Debugf("Fetching Tags for Image %s", i.Name)
+// limitTags keeps only the first @limit Tags of the (sorted) Repositories
+// and drops the Images and Repositories without remaining Tags
+func (r *DockerRegistry) limitTags(limit int) {
+	repos := r.Repos[:0]
 
-	// check Image Name
-	if len(i.Name) == 0 {
-		Log.Fatal("[Internal Error] Trying to fetch all Tags of an Image which Name is not set!!")
-		return errors.NewImageNameNotDefinedError()
-	}
+	for _, repo := range r.Repos {
+		if limit <= 0 { break }
 
-	var uri = fmt.Sprintf("%s/%s/%s/tags/list",
-			i.Repository.DockerRegistry.URI,
-			i.Repository.DockerRegistry.Version,
-			i.Name)
-	var authData = authInfo{
-		token:   nil,
-		authReq: i.Repository.DockerRegistry.Authentication.Required,
-	}
+		images := repo.Images[:0]
+		for _, img := range repo.Images {
+			if limit <= 0 { break }
 
-	client := resty.New()
-	client.SetHeaders(map[string]string{
-		"Docker-Distribution-Api-Version": "registry/2.0",
-		"User-Agent":                      "oima-cli",
-	})
+			if len(img.Tags) > limit { img.Tags = img.Tags[:limit] }
+			limit -= len(img.Tags)
 
-	if i.Repository.DockerRegistry.Authentication.Required {
-		token, err := i.Repository.DockerRegistry.Authentication.Cred.Token.BearerToken.Open()
-		if err != nil {
-			memguard.SafePanic(err)
+			images = append(images, img)
 		}
-		defer token.Destroy()
 
-		client.SetHeader("Authorization", fmt.Sprintf("Bearer %s", token.String()))
-		authData.token = token
+		repo.Images = images
+		repos = append(repos, repo)
 	}
 
-	resp, err := client.R().Get(uri)
-	if err != nil {
-		Log.Criticalf("Error while getting Auth. Token: %s", err.Error())
-		memguard.SafeExit(1)
-	}
+	r.Repos = repos
+}
+
+// FetchAllTags fetches all Tags (with Digests) of the Image
+func (i *Image) FetchAllTags(ctx context.Context) error {
+	return i.fetchTags(ctx, nil)
+}
+
+// fetchTags fetches the Tags (with Digests) of the Image whose Names match @filter
+//noinspection ALL
+func (i *Image) fetchTags(ctx context.Context, filter *Filter) error {
+	Log.Debugf("Fetching Tags for Image %s", i.Name)
 
-	type _tags struct {
-		Tags []string 	`json:"tags"`	// Array of all Tags of an Image
+	// check Image Name
+	if len(i.Name) == 0 {
+		Log.Error("[Internal Error] Trying to fetch all Tags of an Image which Name is not set!!")
+		return errors.NewImageNameNotDefinedError()
 	}
 
-	var tags _tags
+	dockerRegistry := i.Repository.DockerRegistry
 
-	err = json.Unmarshal(resp.Body(), &tags)
+	authData, err := dockerRegistry.authData(ctx, repositoryScope(i.Name, actionPull))
 	if err != nil {
-		Log.Debugf("Response: %s", resp.Body())
-		Log.Fatalf("Error while marshaling Response: %s", err.Error())
-		memguard.SafeExit(1)
+		Log.Errorf("Error while getting the BearerToken: %s", err.Error())
+		return err
 	}
-	var retErr error
-	retErr = nil
+	defer authData.destroy()
 
-	var wg sync.WaitGroup
-	wg.Add(len(tags.Tags))
-
-	for _, v := range tags.Tags {
-		go func(v string) {
-			defer wg.Done()
+	var tagNames []string
+	err = dockerRegistry.sched.do(ctx, func() error {
+		tagNames, err = getImageTags(ctx, authData, i.Name, dockerRegistry.URI, dockerRegistry.Version, dockerRegistry.PageSize)
+		return err
+	})
+	if err != nil {
+		Log.Errorf("Error while fetching Tags of Image '%s': %s", i.Name, err.Error())
+		return err
+	}
 
-			var imageData = imageInfo{
-				name: i.Name,
-				tag:  "",
-			}
+	// only fetch the Digests of matching Tags
+	if filter != nil {
+		var names []string
+		for _, v := range tagNames { if filter.MatchTagName(v) { names = append(names, v) } }
+		tagNames = names
+	}
 
-			newTag := rt.Tag{
-				Name:          "",
-				ContentDigest: "",
-			}
+	var lock sync.Mutex			// Protects i.Tags
+	group, groupCtx := newFetchGroup(ctx, dockerRegistry.Concurrency)
 
-			imageData.tag = v
-			newTag.Name = rt.TagName(v)
+	for _, v := range tagNames {
+		imageData := imageInfo{
+			name: i.Name,
+			tag:  v,
+		}
 
-			// get Image-Tag Digest
-			newTag.ContentDigest, err = getTagDigest(&authData, imageData,
-				i.Repository.DockerRegistry.URI, i.Repository.DockerRegistry.Version)
+		group.do(func() error {
+			// get Image-Tag Digest (and the Platform Manifests of an Image Index)
+			var manifest *tagManifest
+			err := dockerRegistry.sched.do(groupCtx, func() (err error) {
+				manifest, err = getTagManifest(groupCtx, authData, imageData, dockerRegistry.URI, dockerRegistry.Version)
+				return err
+			})
 			if err != nil {
-				Log.Fatalf("Error while getting Image-Tag Digest: %s", err.Error())
-				retErr = err
-				wg.Done()
-				return
+				Log.Errorf("Error while getting Image-Tag Digest of '%s:%s': %s", i.Name, imageData.tag, err.Error())
+				return err
 			}
 
 			// add Tag to the other Tags
-			i.Tags = append(i.Tags, newTag)
-			Log.Debugf("==> Digest (%s:%s): %s", i.Name, imageData.tag, newTag.ContentDigest)
-		}(v)
+			lock.Lock()
+			i.Tags = append(i.Tags, rt.Tag{
+				Name:          rt.TagName(imageData.tag),
+				ContentDigest: manifest.digest,
+				MediaType:     manifest.mediaType,
+				Platforms:     manifest.platforms,
+			})
+			lock.Unlock()
+
+			Log.Debugf("==> Digest (%s:%s): %s (%s, %d Platforms)", i.Name, imageData.tag, manifest.digest,
+				manifest.mediaType, len(manifest.platforms))
+			return nil
+		})
 	}
 
-	// wait for the for loop
-	wg.Wait()
-
-	if retErr != nil { return retErr }
+	// wait for all Digests
+	err = group.wait()
+	if err != nil { return err }
 
 	// TODO: (1) Test Time with a Lot of Repos/ Images/ Tags
 	// TODO: (2) Implement own sort Algorithm to improve runtime?
@@ -552,16 +701,18 @@ func (i *Image) FetchAllTags() error {
 	return nil
 }
 
-func (i* Image) ListImageTags() ([]rt.Tag, error) {
+// ListImageTags returns all Tags of the Image
+// (they are fetched if they were not fetched before)
+func (i* Image) ListImageTags(ctx context.Context) ([]rt.Tag, error) {
 	if len(i.Name) == 0 {
-		Log.Fatal("[Internal Error] Trying to List Image Tags from an Image which Name is not set!!")
+		Log.Error("[Internal Error] Trying to List Image Tags from an Image which Name is not set!!")
 		return nil, errors.NewImageNameNotDefinedError()
 	}
 
 	if i.Tags == nil || len(i.Tags) == 0 {
-		err := i.FetchAllTags()
+		err := i.FetchAllTags(ctx)
 		if err != nil {
-			Log.Fatalf("Error while fetching all Tags of Image '%s': %s", i.Name, err.Error())
+			Log.Errorf("Error while fetching all Tags of Image '%s': %s", i.Name, err.Error())
 			return nil, err
 		}
 	}
@@ -579,152 +730,296 @@ func (i *Image) GetRegistryURI() string { return i.Repository.DockerRegistry.URI
 
 func (i *Image) GetName() string { return i.Name }
 
-func (i *Image) DeleteSignature(t *rt.Tag) {
-	Log.Debugf("Deleting Signature of Tag '%s' from Image '%s'", t.Name, i.Name)
+func (i *Image) SignatureStoreEnabled() bool { return i.Repository.DockerRegistry.sigStoreEnabled }
+
+// DeleteSignature deletes the Signature @signature (or all Signatures, see rt.AllSignatures)
+// of Tag @t from the Signature Store and (for all Signatures) the Trust Data from Notary.
+// All Signatures also include the Signatures of the Platform Manifests of an Image Index.
+// The Signatures following a single deleted Signature are renumbered (see sigstore.DeleteSignature()).
+func (i *Image) DeleteSignature(ctx context.Context, t *rt.Tag, signature string) error {
+	if signature == rt.AllSignatures {
+		Log.Debugf("Deleting all Signatures of Tag '%s' from Image '%s'", t.Name, i.Name)
+	} else {
+		Log.Debugf("Deleting Signature '%s' of Tag '%s' from Image '%s'", signature, t.Name, i.Name)
+	}
 
 	// search for Tag in Tags
-	for _, v := range i.Tags {
+	for iTag := range i.Tags {
+		v := &i.Tags[iTag]
+
 		if v.Name == t.Name {
-			objCtxPath := fmt.Sprintf("%s/%s@%s", s3.PrepareRegPath(i.Repository.DockerRegistry.URI), i.Name,
-				strings.ReplaceAll(string(v.ContentDigest), ":", "="))
-			i.Repository.DockerRegistry.s3Cli.DeleteSignature(objCtxPath, &v)
+			if i.Repository.DockerRegistry.sigStoreEnabled {
+				imagePath := sigstore.ImagePath(i.Repository.DockerRegistry.URI, i.Name)
+
+				// a single Signature is deleted without leaving a Gap in the Numbering,
+				// so the following Signatures have new Names afterwards
+				var remaining []string
+				if signature != rt.AllSignatures {
+					err := sigstore.DeleteSignature(ctx, i.Repository.DockerRegistry.sigStore, imagePath, v.ContentDigest, signature)
+					if err != nil {
+						Log.Errorf("Error while trying to delete Signature '%s' of '%s:%s': %s", signature, i.Name, v.Name, err.Error())
+						return err
+					}
+
+					remaining, err = i.Repository.DockerRegistry.sigStore.List(ctx, imagePath, v.ContentDigest)
+					if err != nil { return err }
+				} else {
+					for _, name := range v.Signatures {
+						err := i.Repository.DockerRegistry.sigStore.Delete(ctx, imagePath, v.ContentDigest, name)
+						if err != nil {
+							Log.Errorf("Error while trying to delete Signature '%s' of '%s:%s': %s", name, i.Name, v.Name, err.Error())
+							return err
+						}
+					}
+				}
+
+				// the Signatures of the Platform Manifests are deleted together with the Index
+				if signature == rt.AllSignatures {
+					for iPlatform := range v.Platforms {
+						p := &v.Platforms[iPlatform]
+
+						for _, name := range p.Signatures {
+							err := i.Repository.DockerRegistry.sigStore.Delete(ctx, imagePath, p.Digest, name)
+							if err != nil {
+								Log.Errorf("Error while trying to delete Signature '%s' of '%s:%s' (%s): %s", name, i.Name, v.Name, p.Platform, err.Error())
+								return err
+							}
+						}
+					}
+				}
+
+				// the Signatures belongs to the Digest, so update all Tags pointing to it
+				for iDigestTag := range i.Tags {
+					digestTag := &i.Tags[iDigestTag]
+					if digestTag.ContentDigest != v.ContentDigest { continue }
+
+					digestTag.Signatures = remaining
+					if signature == rt.AllSignatures {
+						for iPlatform := range digestTag.Platforms { digestTag.Platforms[iPlatform].Signatures = nil }
+					}
+				}
+			}
+
+			// Trust Data can only be removed for the whole Tag
+			if i.Repository.DockerRegistry.notaryEnabled && signature == rt.AllSignatures {
+				err := i.Repository.DockerRegistry.notaryCli.DeleteTarget(notary.GUN(i.Repository.DockerRegistry.URI, i.Name),
+					v.ContentDigest)
+				if err != nil {
+					Log.Errorf("Error while deleting Trust Data of '%s:%s' from Notary Server: %s", i.Name, v.Name, err.Error())
+					return err
+				}
+				v.NotarySignFound = false
+			}
 
-			v.S3SignFound = false
 			Log.Debugf("Signature deleted!")
-			break
+			return nil
 		}
 	}
 
+	return errors.NewTagNotFoundError(i.Name, string(t.Name))
 }
 
+// DeleteManifest deletes the Manifest of Tag @t from the Registry and the Signatures of its
+// Digest (and of the Platform Manifests of an Image Index) from all writable Signature Stores of
+// the Registry (see signatureStores()) and the Trust Data from Notary. All Stores are cleaned up,
+// even if one fails (see errors.SignatureCleanupError). The Registry deletes all Tags which point to the same Digest,
+// so they are removed from the Image, too (see Aliases()).
+func (i *Image) DeleteManifest(ctx context.Context, t *rt.Tag) error {
+	dockerRegistry := i.Repository.DockerRegistry
 
-func (a *Auth) Init() { a.Cred.auth = a }
+	tag, err := i.FindTag(string(t.Name), "")
+	if err != nil { return err }
 
+	digest := tag.ContentDigest
+	digests := tag.Digests()
 
-//noinspection GoNilnesss
-func (c *Credential) Init()	error {
-	// get Password
-	pwdEnclave, err := internal.Cred.GetCredential("password")
-	if err != nil { Log.PanicF("Error while getting Credential from CredStore: %s", err.Error()) }
-	c.Password = pwdEnclave
+	// all (writable) Signature Stores are initialized first, so the
+	// Manifest is not deleted if a Store is not reachable
+	stores, err := dockerRegistry.signatureStores(ctx)
+	if err != nil { return err }
 
-	// get and set Username
-	c.Username = conf.Registry.Username
+	authData, err := dockerRegistry.authData(ctx, repositoryScope(i.Name, actionPull, actionDelete))
+	if err != nil {
+		Log.Errorf("Error while getting the BearerToken: %s", err.Error())
+		return err
+	}
+	defer authData.destroy()
 
-	// get Registry Version
-	c.auth.dockerRegistry.Version, err = getRegistryVersion(c)
+	Log.Debugf("Deleting Manifest %s of Image '%s'", digest, i.Name)
+
+	err = dockerRegistry.sched.do(ctx, func() error {
+		return deleteManifest(ctx, authData, i.Name, digest, dockerRegistry.URI, dockerRegistry.Version)
+	})
 	if err != nil {
-		Log.Errorf("Error while getting Registry API Version: %s", err.Error())
-		memguard.SafeExit(1)
+		Log.Errorf("Error while deleting Manifest %s of '%s:%s': %s", digest, i.Name, tag.Name, err.Error())
+		return err
 	}
 
-	if c.auth.dockerRegistry.Version == V1 {
-		Log.Errorf("Registry API Version is v1. Version 1 isn't supported yet!")
-		memguard.SafeExit(1)
+	// remove the deleted Tags, a Platform Manifest can also be referenced by another Index
+	var trustData bool
+	var tags []rt.Tag
+	referenced := make(map[string]bool)
+
+	for _, v := range i.Tags {
+		if v.ContentDigest == digest {
+			trustData = trustData || v.NotarySignFound
+			continue
+		}
+
+		tags = append(tags, v)
+		for _, d := range v.Digests() { referenced[d] = true }
+	}
+	i.Tags = tags
+
+	// the Manifest is gone, so every Store is cleaned up (even if another Store fails)
+	var errs []error
+
+	imagePath := sigstore.ImagePath(dockerRegistry.URI, i.Name)
+	for _, store := range stores {
+		for _, d := range digests {
+			if referenced[d] { continue }
+
+			signatures, err := store.List(ctx, imagePath, d)
+			if err != nil {
+				Log.Errorf("Error while listing Signatures of '%s@%s': %s", i.Name, d, err.Error())
+				errs = append(errs, err)
+				continue
+			}
+
+			for _, name := range signatures {
+				err = store.Delete(ctx, imagePath, d, name)
+				if err != nil {
+					Log.Errorf("Error while deleting Signature '%s': %s", sigstore.SignaturePath(imagePath, d, name), err.Error())
+					errs = append(errs, err)
+				}
+			}
+		}
 	}
 
-	if c.auth.Required {
-		err = c.getBearerToken()
+	if dockerRegistry.notaryEnabled && trustData {
+		err = dockerRegistry.notaryCli.DeleteTarget(notary.GUN(dockerRegistry.URI, i.Name), digest)
 		if err != nil {
-			Log.Fatalf("Error while getting Bearer Token: %s", err.Error())
-			memguard.SafeExit(1)
+			Log.Errorf("Error while deleting Trust Data of '%s@%s' from Notary Server: %s", i.Name, digest, err.Error())
+			errs = append(errs, err)
 		}
-	} else { Log.Notice("Authentication not required, so no need to get a Bearer Token") }
+	}
 
+	if len(errs) > 0 { return errors.NewSignatureCleanupError(errs) }
+
+	Log.Debugf("Manifest deleted!")
 	return nil
 }
 
-func (c *Credential) getBearerToken() error {
-	// get Bearer Token
-	client := resty.New()
-	client.SetHeaders(map[string]string{
-		"Docker-Distribution-Api-Version": "registry/2.0",
-		"User-Agent": "oima-cli",
-	})
-
-	var password *memguard.LockedBuffer
-
-	// get Password
-	password, err := c.Password.Open()
-	if err != nil { memguard.SafePanic(err) }
-	defer password.Destroy()
+// InspectTag returns the Manifest and the Image Configuration of Tag @t.
+// If the Tag points to an Image Index, the Manifests of all Platforms
+// are returned (in the Order of the Index).
+func (i *Image) InspectTag(ctx context.Context, t *rt.Tag) ([]rt.ManifestInfo, error) {
+	dockerRegistry := i.Repository.DockerRegistry
 
-	client.SetBasicAuth(c.Username, password.String())
+	digests := []string{t.ContentDigest}
+	if t.IsIndex() { digests = t.Digests()[1:] }
 
-	var uri = fmt.Sprintf("%s/api/docker/docker/%s/token",
-		c.auth.dockerRegistry.URI, c.auth.dockerRegistry.Version)
-	resp, err := client.R().Get(uri)
+	authData, err := dockerRegistry.authData(ctx, repositoryScope(i.Name, actionPull))
 	if err != nil {
-		Log.Criticalf("Error while getting Auth. Token: %s", err.Error())
-		return err
+		Log.Errorf("Error while getting the BearerToken: %s", err.Error())
+		return nil, err
 	}
+	defer authData.destroy()
 
-	type _token struct {
-		// The BearerToken is needed to communicate with the Registry API
-		BearerToken		string				`json:"token"`
+	// every Task writes its own Entry, so the Order of the Index is kept
+	manifests := make([]rt.ManifestInfo, len(digests))
+	group, groupCtx := newFetchGroup(ctx, dockerRegistry.Concurrency)
 
-		// Date when the token expires as Unix timestamp
-		ExpiresOn		int64				`json:"expires_in"`
-	}
-	tokenData := _token{}
+	for iDigest := range digests {
+		index := iDigest
 
-	err = json.Unmarshal(resp.Body(), &tokenData)
-	if err != nil {
-		Log.Debugf("Response: %s", resp.Body())
-		Log.Fatalf("Error while marshaling Response: %s", err.Error())
-		return err
+		group.do(func() error {
+			return dockerRegistry.sched.do(groupCtx, func() error {
+				info, err := getImageManifest(groupCtx, authData, i.Name, digests[index], dockerRegistry.URI, dockerRegistry.Version)
+				if err != nil {
+					Log.Errorf("Error while inspecting Manifest '%s' of '%s:%s': %s", digests[index], i.Name, t.Name, err.Error())
+					return err
+				}
+
+				manifests[index] = *info
+				return nil
+			})
+		})
 	}
 
-	// prepare Token
-	if c.Token.BearerToken == nil { c.Token.BearerToken = memguard.NewEnclaveRandom(len(tokenData.BearerToken)) }
+	err = group.wait()
+	if err != nil { return nil, err }
 
-	bearerToken, err := c.Token.BearerToken.Open()
-	if err != nil { memguard.SafePanic(err) }
-	defer bearerToken.Destroy()
+	return manifests, nil
+}
+
+
+func (a *Auth) Init() { a.Cred.auth = a }
 
-	// make bearerToken immutable
-	bearerToken.Melt()
 
-	c.Token.ExpiresOn = tokenData.ExpiresOn
-	bearerToken.Copy([]byte(tokenData.BearerToken))
+//noinspection GoNilnesss
+func (c *Credential) Init(ctx context.Context)	error {
+	// get Password
+	// (the Password is only needed if the Registry requires Authentication)
+	pwdEnclave, err := internal.Cred.GetCredential(config.CredentialName("password", c.auth.dockerRegistry.Name))
+	if err != nil && c.auth.Required {
+		Log.Errorf("Error while getting Credential from CredStore: %s", err.Error())
+		return err
+	}
+	c.Password = pwdEnclave
 
+	// get and set Username
+	c.Username = c.auth.dockerRegistry.regConf.Username
 
-	Log.Debugf("Response: %s", resp.Body())
+	// the Username of the Docker Credentials (see 'docker_config')
+	if len(c.Username) == 0 {
+		if usernameEnclave, err := internal.Cred.GetCredential(config.CredentialName("username", c.auth.dockerRegistry.Name)); err == nil {
+			username, err := usernameEnclave.Open()
+			if err != nil { return err }
 
-	// convert Seconds in BearerToken.ExpiresOn into Unix Timestamp
-	c.Token.ExpiresOn = time.Now().Unix() + c.Token.ExpiresOn
-	Log.Debugf("Bearer Token: '%s'", bearerToken.String())
-	Log.Debugf("Bearer Token Expires On %d (%s)", c.Token.ExpiresOn, time.Unix(c.Token.ExpiresOn, 0))
+			c.Username = string(username.Bytes())
+			username.Destroy()
+		}
+	}
 
-	// return Encrypted Data back to Token Struct
-	c.Token.BearerToken = bearerToken.Seal()
-	memguard.ScrambleBytes([]byte(tokenData.BearerToken))
+	// get Registry Version (and the Authentication Challenge)
+	c.auth.dockerRegistry.Version, c.challenge, err = getRegistryVersion(ctx, c)
+	if err != nil {
+		Log.Errorf("Error while getting Registry API Version: %s", err.Error())
+		return err
+	}
+
+	if c.auth.dockerRegistry.Version == V1 {
+		Log.Errorf("Registry API Version is v1. Version 1 isn't supported yet!")
+		return errors.NewUnsupportedRegistryVersionError(string(V1))
+	}
+
+	// choose Authentication Mode
+	err = c.initMode(ctx)
+	if err != nil {
+		Log.Errorf("Error while initializing Authentication: %s", err.Error())
+		return err
+	}
 
 	return nil
 }
 
 /// ------------- Internal Functions
 
-//noinspection GoNilness
-func getRegistryVersion(c *Credential) (_RegistryVersion, error) {
+// getRegistryVersion returns the API Version of the Registry and the
+// 'WWW-Authenticate' Challenge (if the Registry sent one)
+func getRegistryVersion(ctx context.Context, c *Credential) (_RegistryVersion, *authChallenge, error) {
 	var version _RegistryVersion
-	client := resty.New()
-
-	client.SetHeader("User-Agent", "oima-cli")
+	client := http.NewRestyClient(c.auth.dockerRegistry.httpClient)
 
-	if c.auth.Required {
-		var password *memguard.LockedBuffer
+	// the Request is sent without Credentials, so the Registry
+	// answers with the Challenge of the required Authentication Mode
+	uri := fmt.Sprintf("%s/v2/", c.auth.dockerRegistry.URI)
 
-		// get Password
-		password, err := c.Password.Open()
-		if err != nil { memguard.SafePanic(err) }
-		defer password.Destroy()
-
-		client.SetBasicAuth(c.Username, password.String())
-	}
+	resp, err := http.Retry.Get(ctx, client.R(), uri)
+	if err != nil { return VUNK, nil, err }
 
-	resp, err := client.R().Get(fmt.Sprintf("%s/v2/", c.auth.dockerRegistry.URI))
-	if err != nil { return VUNK, err }
+	if http.RetryableStatus(resp.StatusCode()) { return VUNK, nil, responseError(uri, resp) }
 
 	if resp.StatusCode() == 404 {
 		version = V1
@@ -732,5 +1027,11 @@ func getRegistryVersion(c *Credential) (_RegistryVersion, error) {
 		version = V2
 	}
 
-	return version, nil
-}
\ No newline at end of file
+	var challenge *authChallenge
+	if resp.StatusCode() == 401 {
+		challenge = parseChallenge(resp.Header().Get("WWW-Authenticate"))
+		if challenge != nil { Log.Debugf("Registry sent '%s' Challenge (Realm: '%s', Service: '%s')", challenge.Scheme, challenge.Realm, challenge.Service) }
+	}
+
+	return version, challenge, nil
+}
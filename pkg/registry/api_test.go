@@ -0,0 +1,118 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFetchFilteredLimit(t *testing.T) {
+	var lock sync.Mutex			// Protects fetched
+	fetched := make(map[string]bool)
+
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, req *nethttp.Request) {
+		switch {
+		case req.URL.Path == "/v2/_catalog":
+			// the Catalog is not sorted
+			fmt.Fprint(w, `{"repositories":["team/app-e","team/app-b","team/app-a","team/app-d","team/app-c"]}`)
+		case strings.HasSuffix(req.URL.Path, "/tags/list"):
+			image := strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/v2/"), "/tags/list")
+
+			lock.Lock()
+			fetched[image] = true
+			lock.Unlock()
+
+			fmt.Fprintf(w, `{"name":"%s","tags":["1.0","2.0"]}`, image)
+		case strings.Contains(req.URL.Path, "/manifests/"):
+			w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+			w.Header().Set("Docker-Content-Digest", "sha256:aaaa")
+			fmt.Fprint(w, `{"schemaVersion":2,"mediaType":"application/vnd.docker.distribution.manifest.v2+json"}`)
+		default:
+			w.WriteHeader(nethttp.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	r := &DockerRegistry{
+		Version:		V2,
+		URI:			server.URL,
+		Concurrency:	1,
+		Authentication:	Auth{ Mode: AMNone },
+		sched:			newScheduler(1),
+		httpClient:		server.Client(),
+	}
+
+	filter := &Filter{ Limit: 3 }
+	if err := filter.Compile(); err != nil { t.Fatal(err) }
+
+	if err := r.FetchFiltered(context.Background(), filter); err != nil { t.Fatal(err) }
+
+	// the first Images (by Name) contain the first Tags
+	for _, name := range []string{"team/app-a", "team/app-b"} {
+		if !fetched[name] { t.Errorf("Image '%s' was not fetched", name) }
+	}
+
+	for _, name := range []string{"team/app-d", "team/app-e"} {
+		if fetched[name] { t.Errorf("Image '%s' was fetched, but the Limit was already reached", name) }
+	}
+
+	if got := listTags(r); got != "team/app-a:1.0 team/app-a:2.0 team/app-b:1.0" {
+		t.Errorf("expected the first 3 Tags, got '%s'", got)
+	}
+}
+
+// listTags returns all Tags of @r ('<image>:<tag>', separated by Spaces)
+func listTags(r *DockerRegistry) string {
+	var tags []string
+	for _, repo := range r.Repos {
+		for _, img := range repo.Images {
+			for _, tag := range img.Tags { tags = append(tags, fmt.Sprintf("%s:%s", img.Name, tag.Name)) }
+		}
+	}
+
+	return strings.Join(tags, " ")
+}
+
+func TestFetchFilteredLimitConcurrent(t *testing.T) {
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, req *nethttp.Request) {
+		switch {
+		case req.URL.Path == "/v2/_catalog":
+			fmt.Fprint(w, `{"repositories":["team/app-e","team/app-b","team/app-a","team/app-d","team/app-c"]}`)
+		case strings.HasSuffix(req.URL.Path, "/tags/list"):
+			image := strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/v2/"), "/tags/list")
+			fmt.Fprintf(w, `{"name":"%s","tags":["2.0","1.0"]}`, image)
+		case strings.Contains(req.URL.Path, "/manifests/"):
+			w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+			w.Header().Set("Docker-Content-Digest", "sha256:aaaa")
+			fmt.Fprint(w, `{"schemaVersion":2,"mediaType":"application/vnd.docker.distribution.manifest.v2+json"}`)
+		default:
+			w.WriteHeader(nethttp.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// further Images may be fetched concurrently, but the Result is always the same
+	for i := 0; i < 20; i++ {
+		r := &DockerRegistry{
+			Version:		V2,
+			URI:			server.URL,
+			Concurrency:	4,
+			Authentication:	Auth{ Mode: AMNone },
+			sched:			newScheduler(4),
+			httpClient:		server.Client(),
+		}
+
+		filter := &Filter{ Limit: 3 }
+		if err := filter.Compile(); err != nil { t.Fatal(err) }
+
+		if err := r.FetchFiltered(context.Background(), filter); err != nil { t.Fatal(err) }
+
+		if got := listTags(r); got != "team/app-a:1.0 team/app-a:2.0 team/app-b:1.0" {
+			t.Fatalf("expected the first 3 Tags, got '%s'", got)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-resty/resty"
+	"strings"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/errors"
+	"github.com/fabmation-gmbh/oima/pkg/http"
+)
+
+type _AuthMode string // Describes how Requests to the Registry API are authenticated
+const (
+	AMAuto		_AuthMode	= "auto"	// Detect the Mode from the Challenge of the Registry ('/v2/')
+	AMNone		_AuthMode	= "none"	// No Authentication (anonymous Access)
+	AMBasic		_AuthMode	= "basic"	// HTTP Basic Authentication (eg. 'registry:2' with htpasswd)
+	AMBearer	_AuthMode	= "bearer"	// Bearer Token (see _TokenFlow)
+)
+
+
+/// >>>>>>>>>> Functions <<<<<<<<<< ///
+
+// detectAuthMode returns the Authentication Mode requested by the Challenge.
+// If the Registry did not send a Challenge, but the User configured Credentials,
+// the Bearer Mode is used (JFrog Artifactory does not need to send a Challenge).
+func detectAuthMode(challenge *authChallenge, credentials bool) _AuthMode {
+	switch {
+	case challenge != nil && challenge.Scheme == "bearer":	return AMBearer
+	case challenge != nil && challenge.Scheme == "basic":	return AMBasic
+	case credentials:										return AMBearer
+	default:												return AMNone
+	}
+}
+
+// initMode sets the Authentication Mode (configured or detected) and prepares it
+func (c *Credential) initMode(ctx context.Context) error {
+	c.auth.Mode = _AuthMode(strings.ToLower(c.auth.dockerRegistry.regConf.AuthMode))
+
+	switch c.auth.Mode {
+	case "", AMAuto:
+		c.auth.Mode = detectAuthMode(c.challenge, c.auth.Required)
+		Log.Debugf("Detected Authentication Mode '%s'", c.auth.Mode)
+
+	case AMNone, AMBasic, AMBearer:
+
+	default:
+		return errors.NewInvalidConfigValueError("registry.auth_mode", string(c.auth.Mode),
+			[]string{string(AMAuto), string(AMNone), string(AMBasic), string(AMBearer)})
+	}
+
+	switch c.auth.Mode {
+	case AMBasic:
+		if !c.auth.Required {
+			return errors.NewAuthenticationErrorMsg("Registry requires Basic Authentication, but no Credentials are configured")
+		}
+
+		// check the Credentials now, the same way it is done for the Bearer Token
+		err := c.checkBasicAuth(ctx)
+		if err != nil { return err }
+
+	case AMBearer:
+		return c.initTokenFlow(ctx)
+
+	default:
+		Log.Debug("Authentication not required, so no need to get a Bearer Token")
+	}
+
+	return nil
+}
+
+// initTokenFlow chooses the Token Flow (configured or detected) used in the Bearer Mode
+func (c *Credential) initTokenFlow(ctx context.Context) error {
+	c.tokenFlow = _TokenFlow(strings.ToLower(c.auth.dockerRegistry.regConf.TokenFlow))
+
+	switch c.tokenFlow {
+	case "", TFAuto:
+		if c.challenge.bearerChallenge() { c.tokenFlow = TFChallenge } else { c.tokenFlow = TFArtifactory }
+
+	case TFChallenge:
+		if !c.challenge.bearerChallenge() {
+			return errors.NewAuthenticationErrorMsg("Token Flow 'challenge' configured, but the Registry did not send a Bearer Challenge")
+		}
+
+	case TFArtifactory:
+
+	default:
+		return errors.NewInvalidConfigValueError("registry.token_flow", string(c.tokenFlow),
+			[]string{string(TFAuto), string(TFChallenge), string(TFArtifactory)})
+	}
+	Log.Debugf("Using Token Flow '%s'", c.tokenFlow)
+
+	if c.tokenFlow == TFArtifactory {
+		// the Artifactory Token is not scoped, so check the Credentials now
+		token, err := c.getToken(ctx, "")
+		if err != nil { return err }
+		token.Destroy()
+	}
+
+	return nil
+}
+
+// checkBasicAuth checks the configured Credentials against '/v2/'
+func (c *Credential) checkBasicAuth(ctx context.Context) error {
+	auth, err := c.auth.dockerRegistry.authData(ctx, "")
+	if err != nil { return err }
+	defer auth.destroy()
+
+	uri := fmt.Sprintf("%s/v2/", c.auth.dockerRegistry.URI)
+
+	resp, err := http.Retry.Get(ctx, newAPIClient(auth).R(), uri)
+	if err != nil { return err }
+
+	if resp.StatusCode() == 401 || resp.StatusCode() == 403 {
+		return errors.NewAuthenticationError(c.Username, resp.StatusCode())
+	}
+
+	if resp.StatusCode() != 200 { return responseError(uri, resp) }
+
+	return nil
+}
+
+// authData returns the Authentication Information for @scope, depending on the
+// Authentication Mode it contains the opened Password or the Bearer Token for @scope.
+// The Buffers must be destroyed with authInfo.destroy() after use.
+func (r *DockerRegistry) authData(ctx context.Context, scope string) (*authInfo, error) {
+	data := &authInfo{mode: r.Authentication.Mode, client: r.httpClient}
+
+	switch data.mode {
+	case AMBasic:
+		password, err := r.Authentication.Cred.Password.Open()
+		if err != nil { return nil, err }
+
+		data.username = r.Authentication.Cred.Username
+		data.secret = password
+
+	case AMBearer:
+		token, err := r.Authentication.Cred.getToken(ctx, scope)
+		if err != nil { return nil, err }
+
+		data.secret = token
+	}
+
+	return data, nil
+}
+
+// apply adds the Authentication (if any) to all Requests of @client
+func (a *authInfo) apply(client *resty.Client) {
+	switch a.mode {
+	case AMBasic:	client.SetBasicAuth(a.username, a.secret.String())
+	case AMBearer:	client.SetHeader("Authorization", fmt.Sprintf("Bearer %s", a.secret.String()))
+	}
+}
+
+// destroy wipes the opened Password/ Token
+func (a *authInfo) destroy() {
+	if a.secret != nil { a.secret.Destroy() }
+}
@@ -0,0 +1,131 @@
+package registry
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/fabmation-gmbh/oima/pkg/errors"
+	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
+)
+
+type _SignState string // Describes which Tags are selected by their Signatures
+const (
+	SSAny		_SignState	= ""			// Select all Tags
+	SSSigned	_SignState	= "signed"		// Select only Tags with a Signature (Signature Store or Notary)
+	SSUnsigned	_SignState	= "unsigned"	// Select only Tags without any Signature
+)
+
+// Filter selects the Repositories, Images and Tags which are fetched from the Registry.
+// Empty Fields match everything.
+type Filter struct {
+	Repository		string				// Pattern of the Repository Name (eg. 'team/*')
+	Image			string				// Pattern of the full Image Name (eg. 'team/nginx*')
+	Regex			bool				// @Repository and @Image are Regular Expressions instead of Globs
+	Tag				string				// Regular Expression of the Tag Name
+	Digest			string				// Prefix of the Content Digest (eg. 'sha256:ab12')
+	SignState		_SignState			// Select Tags by their Signatures
+	Limit			int					// Stop fetching Images after this Number of matching Tags (0 = unlimited)
+
+	repository		func(string) bool	// compiled @Repository
+	image			func(string) bool	// compiled @Image
+	tag				*regexp.Regexp		// compiled @Tag
+}
+
+
+/// >>>>>>>>>> Functions <<<<<<<<<< ///
+
+// Compile checks and compiles the Patterns of the Filter
+func (f *Filter) Compile() error {
+	var err error
+
+	f.repository, err = compilePattern(f.Repository, f.Regex)
+	if err != nil { return err }
+
+	f.image, err = compilePattern(f.Image, f.Regex)
+	if err != nil { return err }
+
+	f.tag = nil
+	if len(f.Tag) > 0 {
+		f.tag, err = regexp.Compile(f.Tag)
+		if err != nil { return errors.NewInvalidFilterError(f.Tag, err) }
+	}
+
+	switch f.SignState {
+	case SSAny, SSSigned, SSUnsigned:
+	default:
+		return errors.NewInvalidConfigValueError("SignState", string(f.SignState), []string{string(SSSigned), string(SSUnsigned)})
+	}
+
+	return nil
+}
+
+// MatchImage returns true if the Image @name (and its Repository) matches the Filter
+func (f *Filter) MatchImage(name string) bool {
+	if f == nil { return true }
+
+	if f.repository != nil && !f.repository(repositoryName(name)) { return false }
+	if f.image != nil && !f.image(name) { return false }
+
+	return true
+}
+
+// MatchTagName returns true if the Tag @name matches the Filter
+func (f *Filter) MatchTagName(name string) bool {
+	return f == nil || f.tag == nil || f.tag.MatchString(name)
+}
+
+// MatchTag returns true if the (fetched) Tag @tag matches the Filter.
+// The Digest and the Signatures of the Tag must be fetched.
+func (f *Filter) MatchTag(tag rt.Tag) bool {
+	if f == nil { return true }
+
+	if !f.MatchTagName(string(tag.Name)) { return false }
+	if !strings.HasPrefix(tag.ContentDigest, f.Digest) { return false }
+
+	signed := tag.SignatureCount() > 0 || tag.NotarySignFound
+
+	switch f.SignState {
+	case SSSigned:		return signed
+	case SSUnsigned:	return !signed
+	}
+
+	return true
+}
+
+// tagFilterActive returns true if the Filter selects Tags
+// (Images without matching Tags are dropped in this Case)
+func (f *Filter) tagFilterActive() bool {
+	return f != nil && (f.tag != nil || len(f.Digest) > 0 || f.SignState != SSAny)
+}
+
+// compilePattern returns a Function which matches @pattern (Glob or Regex).
+// Returns nil if @pattern is empty.
+func compilePattern(pattern string, regex bool) (func(string) bool, error) {
+	if len(pattern) == 0 { return nil, nil }
+
+	if regex {
+		re, err := regexp.Compile(pattern)
+		if err != nil { return nil, errors.NewInvalidFilterError(pattern, err) }
+
+		return re.MatchString, nil
+	}
+
+	// check the Glob Syntax once, so Match can not fail later
+	if _, err := path.Match(pattern, ""); err != nil { return nil, errors.NewInvalidFilterError(pattern, err) }
+
+	return func(s string) bool {
+		ok, _ := path.Match(pattern, s)
+		return ok
+	}, nil
+}
+
+// repositoryName returns the Name of the Repository which contains the Image @name
+// Example:
+//		Input:		testing/unstable/jira
+//		Output:		testing/unstable
+func repositoryName(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 { return name[:i] }
+
+	return "/"
+}
@@ -1,23 +1,28 @@
 package interfaces
 
+import (
+	"context"
+
+	"github.com/awnumar/memguard"
+)
 
 
 // Holds all Informations that are needed to talk with the Registry API
 type Registry interface {
 	// Initialize Registry (and all required Components (Auth, ...))
-	Init()				error
+	Init(ctx context.Context)				error
 
 	// List all Repositories found in the Registry
-	ListRepositories()  []BaseRepository
+	ListRepositories(ctx context.Context)	([]BaseRepository, error)
 
 	// TODO
 	// Test Authentication, API Version (=> Compatibility)
 	CheckRegistry()		(bool, error)
 
 	// Fetch _all_ Informations (Repos->Images->Tags) available in the Registry
-	FetchAll()			error
+	FetchAll(ctx context.Context)			error
 
-	// Stats() returns Statistics of the Registry
+	// Stats() returns Statistics of the (already fetched) Registry
 	Stats()				Stats
 
 	/// >>>>>>>>>> Getter & Setter <<<<<<<<<<
@@ -29,7 +34,7 @@ type Registry interface {
 type BaseCredential interface {
 	Init(cred *BaseCredential)	error		// Checks and "Initializes" the Credential Struct
 
-	getBearerToken()			error		// getBearerToken() sets/ renews the Token in Credential.Token.BearerToken
+	getToken(context.Context, string)	(*memguard.LockedBuffer, error)	// getToken() returns the (cached or renewed) Bearer Token for a Scope
 }
 
 type Auth interface {
@@ -38,25 +43,31 @@ type Auth interface {
 
 // A (Docker) Repository is (for example) the 'atlassian-jira' in 'docker.reg.local/atlassian-jira:v1.0.0'
 type BaseRepository interface {
-	ListImages()		([]BaseImage, error)	// List all available Images
-	FetchAllImages()	error					// Fetch _all_ Image Informations (Images->Tags) available in the Repository
+	ListImages(ctx context.Context)		([]BaseImage, error)	// List all available Images
+	FetchAllImages(ctx context.Context)	error					// Fetch _all_ Image Informations (Images->Tags) available in the Repository
 }
 
 // An Image represents a **single** Docker Image (with TagName)
 type BaseImage interface {
-	ListImageTags() 	([]Tag, error)		// List all available Tags of a Image
-	FetchAllTags()		error					// Fetch _all_ Tags from the Image
+	ListImageTags(ctx context.Context) 	([]Tag, error)		// List all available Tags of a Image
+	FetchAllTags(ctx context.Context)		error			// Fetch _all_ Tags from the Image
 
-	DeleteSignature(*Tag)					// Delete the Signature of an Tag from the S3-Server
+	DeleteSignature(context.Context, *Tag, string)	error				// Delete one (or all) Signatures of an Tag from the Signature Store and Notary-Server
+	ReadSignature(context.Context, *Tag, string)	([]byte, error)		// Returns the Content of a Signature of an Tag
+	DeleteManifest(context.Context, *Tag)	error						// Delete the Manifest of an Tag (with all Tags pointing to it) and its Signatures
+	InspectTag(context.Context, *Tag)	([]ManifestInfo, error)				// Returns the Manifests (with Image Configuration) of an Tag (one per Platform)
 
 
 	/// >>>>>>>>>> Getter & Setter <<<<<<<<<<
 
 	GetTags()			[]Tag					// Return copy of Tag slice
+	GetDigests()		[]Digest				// Return all Digests with the Tags pointing to them
+	Aliases(*Tag)		[]TagName				// Return the Names of all other Tags pointing to the Digest of a Tag
 	GetTagsPtr()		*[]Tag					// Return Pointer to Tags slice
 	SetTags([]Tag)								// Overwrite Field 'Tags' with the new Tag slice
 
 	GetName()			string					// Return Name of the Image
+	SignatureStoreEnabled()	bool				// Return true if the Registry of the Image has a Signature Store
 
 	GetRegistryURI()	string					// Returns the URI of the Registry
 }
@@ -64,10 +75,74 @@ type BaseImage interface {
 
 
 
+// AllSignatures can be passed to BaseImage.DeleteSignature()
+// to delete all Signatures of a Tag
+const AllSignatures = ""
+
+// Media Types of the Manifests (and Indexes) returned by the Registry
+const (
+	MediaTypeDockerManifest		= "application/vnd.docker.distribution.manifest.v2+json"
+	MediaTypeDockerManifestList	= "application/vnd.docker.distribution.manifest.list.v2+json"
+	MediaTypeOCIManifest		= "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeOCIIndex			= "application/vnd.oci.image.index.v1+json"
+)
+
 // Describes a Tag of a Image in a Repository
 // Implements the @tag Interface
 type Tag struct {
-	Name          TagName // Image Tag (eg 'v1.0.0')
-	ContentDigest string  // Docker Content Digest
-	S3SignFound   bool    // Is a Signature found on the S3 Server
-}
\ No newline at end of file
+	Name          TagName  // Image Tag (eg 'v1.0.0')
+	ContentDigest string   // Docker Content Digest
+	MediaType     string   // Media Type of the Manifest (eg. MediaTypeOCIIndex)
+	Signatures    []string // Names of all Signatures found in the Signature Store (eg 'signature-1')
+	NotarySignFound bool   // Is Trust Data found on the Notary Server
+
+	// Manifests of all Platforms, only set if the Tag points to
+	// an OCI Image Index or a Docker Manifest List (Multi-Arch Image)
+	Platforms     []PlatformManifest
+}
+
+// PlatformManifest is the Manifest of a single Platform in an Image Index
+type PlatformManifest struct {
+	Platform      string   // OS and Architecture (eg. 'linux/arm64/v8')
+	MediaType     string   // Media Type of the Manifest
+	Digest        string   // Digest of the Manifest
+	Signatures    []string // Names of all Signatures of the Digest found in the Signature Store
+}
+
+// Digest is a Manifest (or Image Index) in the Registry with all Tags pointing to it.
+// Signatures and Trust Data belong to the Digest and not to a Tag, so all Tags
+// of a Digest (the Aliases, eg. 'latest', '1.4' and '1.4.2') are signed together.
+type Digest struct {
+	Digest          string    // Docker Content Digest
+	MediaType       string    // Media Type of the Manifest (eg. MediaTypeOCIIndex)
+	Tags            []TagName // Names of all Tags pointing to the Digest (sorted)
+	Signatures      []string  // Names of all Signatures found in the Signature Store
+	NotarySignFound bool      // Is Trust Data found on the Notary Server (for at least one Tag)
+
+	// Manifests of all Platforms, only set for an Image Index
+	Platforms       []PlatformManifest
+}
+
+// SignatureCount returns the Number of Signatures found in the Signature Store
+func (t *Tag) SignatureCount() int { return len(t.Signatures) }
+
+// IsIndex returns true if the Tag points to an OCI Image Index or a Docker Manifest List
+func (t *Tag) IsIndex() bool {
+	return t.MediaType == MediaTypeOCIIndex || t.MediaType == MediaTypeDockerManifestList
+}
+
+// Digests returns the Digest of the Tag and the Digests of all Platform Manifests
+func (t *Tag) Digests() []string {
+	digests := []string{t.ContentDigest}
+	for _, p := range t.Platforms { digests = append(digests, p.Digest) }
+
+	return digests
+}
+
+// Aliases returns the Names of all Tags of the Digest except @name
+func (d *Digest) Aliases(name TagName) []TagName {
+	var aliases []TagName
+	for _, v := range d.Tags { if v != name { aliases = append(aliases, v) } }
+
+	return aliases
+}
@@ -16,4 +16,7 @@ type Stats struct {
 
 	// Number of Signatures found on the S3 Server
 	S3Signatures		int
+
+	// Number of Tags with Trust Data on the Notary Server
+	NotarySignatures	int
 }
\ No newline at end of file
@@ -0,0 +1,45 @@
+package interfaces
+
+import (
+	"fmt"
+	"time"
+)
+
+// ManifestInfo describes the Manifest (and the Image Configuration) of a single Platform
+type ManifestInfo struct {
+	Digest			string				// Digest of the Manifest
+	MediaType		string				// Media Type of the Manifest
+	Platform		string				// OS and Architecture of the Image Configuration (eg. 'linux/arm64/v8')
+
+	Created			time.Time			// Creation Time of the Image (zero if not set)
+	Architecture	string				// CPU Architecture (eg. 'amd64')
+	OS				string				// Operating System (eg. 'linux')
+
+	Size			int64				// Compressed Size of the Image Configuration and all Layers (in Bytes)
+	Layers			[]LayerInfo			// Layers of the Image (the Base Layer first)
+	Labels			map[string]string	// Labels of the Image Configuration
+}
+
+// LayerInfo describes a single Layer of a Manifest
+type LayerInfo struct {
+	Digest			string				// Digest of the (compressed) Layer Blob
+	MediaType		string				// Media Type of the Layer
+	Size			int64				// Compressed Size (in Bytes)
+}
+
+// FormatSize returns @size in a human readable Format (Base 1024)
+// Example:
+//		Input:		1536
+//		Output:		1.5 KiB
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit { return fmt.Sprintf("%d B", size) }
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
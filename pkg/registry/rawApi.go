@@ -1,17 +1,35 @@
 package registry
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/awnumar/memguard"
 	"github.com/go-resty/resty"
+	nethttp "net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
 
 	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/errors"
+	"github.com/fabmation-gmbh/oima/pkg/http"
+	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
 )
 
+// Default Number of Entries per Page (used for '_catalog' and 'tags/list')
+const defaultPageSize = 100
+
+// linkNextRegex matches the URI of the next Page in a 'Link' Header
+// Example: </v2/_catalog?last=nginx&n=100>; rel="next"
+var linkNextRegex = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="?next"?`)
+
 type authInfo struct {
-	token	*memguard.LockedBuffer
-	authReq	bool
+	mode		_AuthMode				// Authentication Mode
+	username	string					// Username (only used in the Basic Mode)
+	secret		*memguard.LockedBuffer	// Password (Basic Mode) or Bearer Token (Bearer Mode)
+	client		*nethttp.Client			// HTTP Client of the Registry (see http.NewClient)
 }
 
 type imageInfo struct {
@@ -19,71 +37,437 @@ type imageInfo struct {
 	tag		string			// Tag Name
 }
 
+// tagManifest describes the Manifest a Tag points to
+type tagManifest struct {
+	digest		string					// Digest of the Manifest (or Index)
+	mediaType	string					// Media Type of the Manifest
+	platforms	[]rt.PlatformManifest	// Platform Manifests (only set for an Image Index)
+}
+
+// _manifestBody contains the Fields of a Manifest (or Index) which are needed to
+// detect the Media Type and to list the Platform Manifests of an Image Index
+type _manifestBody struct {
+	MediaType	string		`json:"mediaType"`
+
+	Manifests	[]struct {
+		MediaType	string		`json:"mediaType"`
+		Digest		string		`json:"digest"`
+		Platform	_platform	`json:"platform"`
+	} `json:"manifests"`
+}
+
+// _platform is the Platform of a Manifest in an Image Index
+type _platform struct {
+	Architecture	string		`json:"architecture"`
+	OS				string		`json:"os"`
+	Variant			string		`json:"variant"`
+}
+
+// _descriptor references a Blob (or Manifest) in a Manifest
+type _descriptor struct {
+	MediaType	string		`json:"mediaType"`
+	Digest		string		`json:"digest"`
+	Size		int64		`json:"size"`
+}
+
+// _imageManifest contains the Fields of the Manifest of a single Platform
+type _imageManifest struct {
+	MediaType	string			`json:"mediaType"`
+	Config		_descriptor		`json:"config"`
+	Layers		[]_descriptor	`json:"layers"`
+}
+
+// _imageConfig contains the Fields of the Image Configuration which are shown by 'image inspect'
+type _imageConfig struct {
+	_platform
+
+	Created		*time.Time		`json:"created"`
+	Config		struct {
+		Labels	map[string]string	`json:"Labels"`
+	} `json:"config"`
+}
+
+// Manifest Media Types requested from the Registry (sent as 'Accept' Header)
+var manifestMediaTypes = []string{
+	rt.MediaTypeOCIIndex,
+	rt.MediaTypeDockerManifestList,
+	rt.MediaTypeOCIManifest,
+	rt.MediaTypeDockerManifest,
+}
+
 
 //noinspection GoNilness
 func getRegistryCatalog(
+	ctx context.Context,
 	auth *authInfo,
 	regURI string,
-	version _RegistryVersion) ([]string, error) {
+	version _RegistryVersion,
+	pageSize int) ([]string, error) {
 
 	var uri = fmt.Sprintf("%s/%s/_catalog", regURI, version)
 
-	client := resty.New()
-	client.SetHeaders(map[string]string{
-		"Docker-Distribution-Api-Version": "registry/2.0",
-		"User-Agent":                      "oima-cli",
+	type response struct {
+		Entries []string `json:"repositories"`
+	}
+
+	var entries []string
+
+	err := getAllPages(ctx, newAPIClient(auth), uri, pageSize, func(body []byte) ([]string, error) {
+		var result response
+
+		err := json.Unmarshal(body, &result)
+		if err != nil {
+			Log.Debugf("Response: %s", body)
+			Log.Errorf("Error while marshaling Response: %s", err.Error())
+			return nil, err
+		}
+
+		entries = append(entries, result.Entries...)
+
+		return result.Entries, nil
 	})
+	if err != nil { return nil, err }
 
-	if auth.authReq {
-		client.SetHeader("Authorization", fmt.Sprintf("Bearer %s", auth.token.String()))
+	return entries, nil
+}
+
+func getImageTags(ctx context.Context, auth *authInfo, image string, regURI string, version _RegistryVersion, pageSize int) ([]string, error) {
+	var uri = fmt.Sprintf("%s/%s/%s/tags/list", regURI, version, image)
+
+	type _tags struct {
+		Tags []string 	`json:"tags"`	// Array of all Tags of an Image
 	}
 
-	resp, err := client.R().Get(fmt.Sprintf(uri))
+	var tags []string
+
+	err := getAllPages(ctx, newAPIClient(auth), uri, pageSize, func(body []byte) ([]string, error) {
+		var result _tags
+
+		err := json.Unmarshal(body, &result)
+		if err != nil {
+			Log.Debugf("Response: %s", body)
+			Log.Errorf("Error while marshaling Response: %s", err.Error())
+			return nil, err
+		}
+
+		tags = append(tags, result.Tags...)
+
+		return result.Tags, nil
+	})
+	if err != nil { return nil, err }
+
+	return tags, nil
+}
+
+// getTagManifest requests the Manifest of the Tag @image.tag and returns its Digest, Media Type
+// and (for an Image Index) the Manifests of all Platforms. All known Media Types are accepted,
+// so the Registry does not convert an Image Index into the Manifest of a single Platform
+// (which has a different Digest than the signed Index).
+func getTagManifest(ctx context.Context, auth *authInfo, image imageInfo, regURI string, version _RegistryVersion) (*tagManifest, error) {
+	var uri = fmt.Sprintf("%s/%s/%s/manifests/%s", regURI, version, image.name, image.tag)
+
+	resp, err := getManifest(ctx, auth, uri)
+	if err != nil { return nil, err }
+
+	// the Digest is needed to find the Signatures, so an empty Digest is never returned
+	manifest := &tagManifest{ digest: resp.Header().Get("Docker-Content-Digest") }
+	if len(manifest.digest) == 0 {
+		return nil, errors.NewRegistryRequestErrorMsg(fmt.Sprintf("Registry did not return a 'Docker-Content-Digest' for '%s'", uri))
+	}
+
+	var body _manifestBody
+
+	err = json.Unmarshal(resp.Body(), &body)
 	if err != nil {
-		Log.Criticalf("Error while getting Auth. Token: %s", err.Error())
+		Log.Debugf("Response: %s", resp.Body())
+		Log.Errorf("Error while marshaling Response: %s", err.Error())
 		return nil, err
 	}
 
-	type response struct {
-		Entries []string `json:"repositories"`
+	manifest.mediaType = manifestMediaType(resp, body.MediaType, body.Manifests != nil)
+
+	if manifest.mediaType != rt.MediaTypeOCIIndex && manifest.mediaType != rt.MediaTypeDockerManifestList {
+		return manifest, nil
 	}
 
-	var result response
-	var entries []string
+	for _, v := range body.Manifests {
+		manifest.platforms = append(manifest.platforms, rt.PlatformManifest{
+			Platform:  v.Platform.String(),
+			MediaType: v.MediaType,
+			Digest:    v.Digest,
+		})
+	}
+
+	return manifest, nil
+}
+
+// getImageManifest requests the Manifest @digest of the Image @image and its Image Configuration
+// and returns the Informations of both. @digest must point to the Manifest of a single
+// Platform, an Image Index has no Image Configuration.
+func getImageManifest(ctx context.Context, auth *authInfo, image string, digest string, regURI string, version _RegistryVersion) (*rt.ManifestInfo, error) {
+	var uri = fmt.Sprintf("%s/%s/%s/manifests/%s", regURI, version, image, digest)
+
+	resp, err := getManifest(ctx, auth, uri)
+	if err != nil { return nil, err }
 
-	err = json.Unmarshal(resp.Body(), &result)
+	var body _imageManifest
+
+	err = json.Unmarshal(resp.Body(), &body)
 	if err != nil {
 		Log.Debugf("Response: %s", resp.Body())
-		Log.Fatalf("Error while marshaling Response: %s", err.Error())
-		memguard.SafeExit(1)
+		Log.Errorf("Error while marshaling Response: %s", err.Error())
+		return nil, err
 	}
 
-	for _, v := range result.Entries {
-		entries = append(entries, v)
+	info := &rt.ManifestInfo{
+		Digest:    digest,
+		MediaType: manifestMediaType(resp, body.MediaType, false),
+		Size:      body.Config.Size,
+		Labels:    map[string]string{},
 	}
 
-	return entries, nil
+	if info.MediaType == rt.MediaTypeOCIIndex || info.MediaType == rt.MediaTypeDockerManifestList {
+		return nil, errors.NewRegistryRequestErrorMsg(fmt.Sprintf("'%s' is an Image Index and not the Manifest of a single Platform", uri))
+	}
+
+	for _, v := range body.Layers {
+		info.Layers = append(info.Layers, rt.LayerInfo{
+			Digest:    v.Digest,
+			MediaType: v.MediaType,
+			Size:      v.Size,
+		})
+		info.Size += v.Size
+	}
+
+	// download the Image Configuration
+	if len(body.Config.Digest) == 0 {
+		return nil, errors.NewRegistryRequestErrorMsg(fmt.Sprintf("Manifest '%s' does not reference an Image Configuration", uri))
+	}
+
+	var configURI = fmt.Sprintf("%s/%s/%s/blobs/%s", regURI, version, image, body.Config.Digest)
+
+	resp, err = http.Retry.Get(ctx, newAPIClient(auth).R(), configURI)
+	if err != nil {
+		Log.Criticalf("Error while requesting '%s': %s", configURI, err.Error())
+		return nil, err
+	}
+
+	if resp.StatusCode() != 200 {
+		Log.Debugf("Response: %s", resp.Body())
+		return nil, responseError(configURI, resp)
+	}
+
+	var imageConfig _imageConfig
+
+	err = json.Unmarshal(resp.Body(), &imageConfig)
+	if err != nil {
+		Log.Debugf("Response: %s", resp.Body())
+		Log.Errorf("Error while marshaling Response: %s", err.Error())
+		return nil, err
+	}
+
+	info.Architecture = imageConfig.Architecture
+	info.OS = imageConfig.OS
+	info.Platform = imageConfig._platform.String()
+	if imageConfig.Created != nil { info.Created = *imageConfig.Created }
+	for k, v := range imageConfig.Config.Labels { info.Labels[k] = v }
+
+	return info, nil
 }
 
-func getTagDigest(auth *authInfo, image imageInfo, regURI string, version _RegistryVersion) (string, error) {
-	var uri = fmt.Sprintf("%s/%s/%s/manifests/%s", regURI, version, image.name, image.tag)
+// deleteManifest deletes the Manifest (or Index) @digest of the Image @image.
+// The Registry deletes all Tags which point to @digest, too.
+func deleteManifest(ctx context.Context, auth *authInfo, image string, digest string, regURI string, version _RegistryVersion) error {
+	var uri = fmt.Sprintf("%s/%s/%s/manifests/%s", regURI, version, image, digest)
+
+	client := newAPIClient(auth)
+
+	err := http.Retry.Do(ctx, func() error {
+		resp, err := client.R().SetContext(ctx).Delete(uri)
+		if err != nil { return err }
 
-	client := resty.New()
-	client.SetHeaders(map[string]string{
-		"Accept": "application/vnd.docker.distribution.manifest.v2+json",
-		"Docker-Distribution-Api-Version": "registry/2.0",
-		"User-Agent":                      "oima-cli",
+		switch resp.StatusCode() {
+		case 200, 202:
+			return nil
+		case 405:
+			return errors.NewRegistryRequestErrorMsg(fmt.Sprintf("Registry does not allow to delete '%s' (Status 405, Deletion is disabled)", uri))
+		}
+
+		Log.Debugf("Response: %s", resp.Body())
+		return responseError(uri, resp)
 	})
+	if err != nil {
+		Log.Criticalf("Error while deleting '%s': %s", uri, err.Error())
+		return err
+	}
 
-	if auth.authReq {
-		client.SetHeader("Authorization", fmt.Sprintf("Bearer %s", auth.token.String()))
+	return nil
+}
+
+// manifestExists checks with a HEAD Request whether the Manifest @digest of @image still exists.
+// Only a Status 404 is reported as missing, all other Errors are returned.
+func manifestExists(ctx context.Context, auth *authInfo, image string, digest string, regURI string, version _RegistryVersion) (bool, error) {
+	var uri = fmt.Sprintf("%s/%s/%s/manifests/%s", regURI, version, image, digest)
+
+	client := newAPIClient(auth)
+	client.SetHeader("Accept", strings.Join(manifestMediaTypes, ", "))
+
+	var exists bool
+	err := http.Retry.Do(ctx, func() error {
+		resp, err := client.R().SetContext(ctx).Head(uri)
+		if err != nil { return err }
+
+		switch resp.StatusCode() {
+		case 200:
+			exists = true
+			return nil
+		case 404:
+			exists = false
+			return nil
+		}
+
+		return responseError(uri, resp)
+	})
+	if err != nil {
+		Log.Criticalf("Error while requesting '%s': %s", uri, err.Error())
+		return false, err
 	}
 
-	resp, err := client.R().Get(fmt.Sprintf(uri))
+	return exists, nil
+}
+
+
+/// >>>>> Helper <<<<<
+
+// getManifest requests the Manifest (or Index) @uri and accepts all known Media Types,
+// so the Registry does not convert an Image Index into the Manifest of a single Platform
+func getManifest(ctx context.Context, auth *authInfo, uri string) (*resty.Response, error) {
+	client := newAPIClient(auth)
+	client.SetHeader("Accept", strings.Join(manifestMediaTypes, ", "))
+
+	resp, err := http.Retry.Get(ctx, client.R(), uri)
 	if err != nil {
-		Log.Criticalf("Error while getting Auth. Token: %s", err.Error())
-		return "", err
+		Log.Criticalf("Error while requesting '%s': %s", uri, err.Error())
+		return nil, err
+	}
+
+	if resp.StatusCode() != 200 {
+		Log.Debugf("Response: %s", resp.Body())
+		return nil, responseError(uri, resp)
 	}
 
-	return resp.Header().Get("Docker-Content-Digest"), nil
-}
\ No newline at end of file
+	return resp, nil
+}
+
+// manifestMediaType returns the Media Type of the Manifest in @resp. It is taken from the
+// 'Content-Type' Header, because OCI Manifests do not need to contain the 'mediaType' Field
+// (@bodyMediaType). If both are missing, @hasManifests decides if it is an Image Index.
+func manifestMediaType(resp *resty.Response, bodyMediaType string, hasManifests bool) string {
+	mediaType := strings.TrimSpace(strings.Split(resp.Header().Get("Content-Type"), ";")[0])
+	if !knownMediaType(mediaType) { mediaType = bodyMediaType }
+
+	if len(mediaType) == 0 {
+		mediaType = rt.MediaTypeOCIManifest
+		if hasManifests { mediaType = rt.MediaTypeOCIIndex }
+	}
+
+	return mediaType
+}
+
+// knownMediaType returns true if @mediaType is one of the requested Manifest Media Types
+func knownMediaType(mediaType string) bool {
+	for _, v := range manifestMediaTypes {
+		if v == mediaType { return true }
+	}
+
+	return false
+}
+
+// String returns the Platform as '<os>/<architecture>[/<variant>]'
+// Example:
+//		Input:		{ OS: linux, Architecture: arm64, Variant: v8 }
+//		Output:		linux/arm64/v8
+func (p _platform) String() string {
+	if len(p.OS) == 0 && len(p.Architecture) == 0 { return "unknown" }
+
+	platform := p.OS + "/" + p.Architecture
+	if len(p.Variant) > 0 { platform += "/" + p.Variant }
+
+	return platform
+}
+
+// newAPIClient returns a resty Client with the default Headers
+// (and the Authentication of the current Authentication Mode)
+func newAPIClient(auth *authInfo) *resty.Client {
+	client := http.NewRestyClient(auth.client)
+	client.SetHeader("Docker-Distribution-Api-Version", "registry/2.0")
+
+	auth.apply(client)
+
+	return client
+}
+
+// getAllPages requests all Pages of the paginated Endpoint @uri ('_catalog' or 'tags/list').
+// @handle is called with the Body of every Page and must return the Entries of the Page.
+// The next Page is taken from the 'Link' Header, if the Registry does not send one but
+// returned a full Page, the next Page is requested with 'last=<last Entry>'.
+func getAllPages(ctx context.Context, client *resty.Client, uri string, pageSize int, handle func(body []byte) ([]string, error)) error {
+	if pageSize <= 0 { pageSize = defaultPageSize }
+
+	base, err := url.Parse(uri)
+	if err != nil { return err }
+
+	next := fmt.Sprintf("%s?n=%d", uri, pageSize)
+	var lastEntry string
+
+	for len(next) > 0 {
+		Log.Debugf("Requesting Page %s", next)
+
+		resp, err := http.Retry.Get(ctx, client.R(), next)
+		if err != nil {
+			Log.Criticalf("Error while requesting '%s': %s", next, err.Error())
+			return err
+		}
+
+		if resp.StatusCode() != 200 {
+			Log.Debugf("Response: %s", resp.Body())
+			return responseError(next, resp)
+		}
+
+		entries, err := handle(resp.Body())
+		if err != nil { return err }
+
+		next = ""
+
+		if link := linkNextRegex.FindStringSubmatch(resp.Header().Get("Link")); link != nil {
+			ref, err := url.Parse(link[1])
+			if err != nil { return err }
+
+			next = base.ResolveReference(ref).String()
+		} else if len(entries) >= pageSize && entries[len(entries)-1] != lastEntry {
+			lastEntry = entries[len(entries)-1]
+			next = fmt.Sprintf("%s?n=%d&last=%s", uri, pageSize, url.QueryEscape(lastEntry))
+		}
+	}
+
+	return nil
+}
+
+// responseError converts the unexpected Response @resp of the Request @uri
+// into the matching Error of the errors Package.
+// Retryable Status Codes (see http.RetryableStatus) are only returned
+// if all Attempts of the Retry Policy failed.
+func responseError(uri string, resp *resty.Response) error {
+	switch resp.StatusCode() {
+	case 401, 403:
+		return errors.NewAuthenticationErrorMsg(fmt.Sprintf("Registry denied the Access to '%s' (Status %d)", uri, resp.StatusCode()))
+	case 404:
+		return errors.NewResourceNotFoundError(uri)
+	case 429:
+		return errors.NewRateLimitError(uri, resp.Header().Get("Retry-After"))
+	case 502, 503, 504:
+		return errors.NewServiceUnavailableError(uri, resp.StatusCode(), resp.Header().Get("Retry-After"))
+	default:
+		return errors.NewRegistryRequestError(uri, resp.StatusCode())
+	}
+}
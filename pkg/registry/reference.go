@@ -0,0 +1,121 @@
+package registry
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/errors"
+	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
+)
+
+// Default Tag if the Reference does not contain a Tag (same as Docker)
+const defaultTag = "latest"
+
+// ParseReference splits an Image Reference into the Image Name and
+// the Tag ('<image>:<tag>') or the Digest ('<image>@sha256:<digest>')
+// Example:
+//		Input:		library/nginx:v1.0.0
+//		Output:		library/nginx, v1.0.0, ""
+func ParseReference(reference string) (image string, tag string, digest string, err error) {
+	if i := strings.Index(reference, "@"); i >= 0 {
+		image, digest = reference[:i], reference[i+1:]
+		if len(image) == 0 || !strings.Contains(digest, ":") { return "", "", "", errors.NewInvalidReferenceError(reference) }
+
+		return image, "", digest, nil
+	}
+
+	image, tag = reference, defaultTag
+
+	// the Tag is separated by the last ':' after the last '/'
+	if i := strings.LastIndex(reference, ":"); i > strings.LastIndex(reference, "/") {
+		image, tag = reference[:i], reference[i+1:]
+	}
+
+	if len(image) == 0 || len(tag) == 0 { return "", "", "", errors.NewInvalidReferenceError(reference) }
+
+	return image, tag, "", nil
+}
+
+// GetImage fetches a single Image (with all Tags and Signatures)
+// without fetching the whole Catalog of the Registry
+func (r *DockerRegistry) GetImage(ctx context.Context, name string) (*Image, error) {
+	repoName := path.Dir(name)
+	if repoName == "." { repoName = "/" }
+
+	repo := &Repository{
+		DockerRegistry: r,
+		Name:           repoName,
+		Images:         nil,
+	}
+
+	img := &Image{
+		Repository: repo,
+		Name:       name,
+		Tags:       nil,
+	}
+
+	err := img.FetchAllTags(ctx)
+	if err != nil {
+		Log.Errorf("Error while Fetching Tags of Image '%s': %s", name, err.Error())
+		return nil, err
+	}
+
+	err = img.fetchSignatureData(ctx)
+	if err != nil { return nil, err }
+
+	repo.Images = []Image{*img}
+
+	return &repo.Images[0], nil
+}
+
+// FindTag returns the Tag with the Name @tag or (if @tag is empty)
+// the first Tag that points to @digest
+func (i *Image) FindTag(tag string, digest string) (*rt.Tag, error) {
+	for iTag := range i.Tags {
+		if len(tag) > 0 && string(i.Tags[iTag].Name) == tag { return &i.Tags[iTag], nil }
+		if len(tag) == 0 && i.Tags[iTag].ContentDigest == digest { return &i.Tags[iTag], nil }
+	}
+
+	if len(tag) == 0 { tag = digest }
+
+	return nil, errors.NewTagNotFoundError(i.Name, tag)
+}
+
+// GetDigests returns all Digests of the Image with the Tags pointing to them
+// (a Digest is listed at the Position of its first Tag)
+func (i *Image) GetDigests() []rt.Digest {
+	var digests []rt.Digest
+	index := make(map[string]int)
+
+	for _, v := range i.Tags {
+		iDigest, ok := index[v.ContentDigest]
+		if !ok {
+			iDigest = len(digests)
+			index[v.ContentDigest] = iDigest
+
+			digests = append(digests, rt.Digest{
+				Digest:     v.ContentDigest,
+				MediaType:  v.MediaType,
+				Signatures: v.Signatures,
+				Platforms:  v.Platforms,
+			})
+		}
+
+		d := &digests[iDigest]
+		d.Tags = append(d.Tags, v.Name)
+		d.NotarySignFound = d.NotarySignFound || v.NotarySignFound
+	}
+
+	return digests
+}
+
+// Aliases returns the Names of all other Tags which point to the same Digest as Tag @t
+func (i *Image) Aliases(t *rt.Tag) []rt.TagName {
+	for _, d := range i.GetDigests() {
+		if d.Digest == t.ContentDigest { return d.Aliases(t.Name) }
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package registry
+
+import (
+	"context"
+	"sync"
+)
+
+// Default Number of concurrent Requests sent to the Registry (and the Signature Store)
+const defaultConcurrency = 8
+
+// scheduler limits the Number of concurrent Requests of a Registry.
+// It is shared by all Catalog, Tag, Digest and Signature Requests, so
+// the Registry never gets more than @concurrency Requests at once.
+// A Slot is only held while a single Request is running (and never while
+// waiting on other Tasks), so nested Fetches can not deadlock.
+type scheduler struct {
+	slots		chan struct{}		// one Entry per running Request
+}
+
+// fetchGroup runs Tasks on a Pool of Workers and waits for them.
+// The Pool of every Group is limited (see newFetchGroup), so a Registry with
+// thousands of Tags does not start a Goroutine per Tag.
+// The first Error cancels the Context of all other Tasks and is returned by wait().
+type fetchGroup struct {
+	tasks		chan func() error	// Tasks waiting for a free Worker
+	workers		int					// Max. Number of Workers
+	started		int					// Number of started Workers
+
+	wg			sync.WaitGroup
+	ctx			context.Context		// Context of the Tasks
+	cancel		context.CancelFunc
+
+	errOnce		sync.Once
+	err			error				// first Error returned by a Task
+}
+
+
+/// >>>>>>>>>> Functions <<<<<<<<<< ///
+
+// newScheduler returns a Scheduler which allows @concurrency Requests at once
+// (the default is used if @concurrency is not positive)
+func newScheduler(concurrency int) *scheduler {
+	if concurrency <= 0 { concurrency = defaultConcurrency }
+
+	return &scheduler{ slots: make(chan struct{}, concurrency) }
+}
+
+// do runs the Request @request as soon as a Slot is free.
+// Returns the Error of @ctx if it is done before a Slot is free.
+// A nil Scheduler does not limit the Requests.
+func (s *scheduler) do(ctx context.Context, request func() error) error {
+	if s == nil { return request() }
+
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-s.slots }()
+
+	// the Context could be done while waiting on the Slot
+	if err := ctx.Err(); err != nil { return err }
+
+	return request()
+}
+
+// newFetchGroup returns a new Group with (at most) @workers Workers and the Context of its Tasks,
+// which is canceled on the first Error (or if @ctx is done).
+// The default Concurrency is used if @workers is not positive.
+func newFetchGroup(ctx context.Context, workers int) (*fetchGroup, context.Context) {
+	if workers <= 0 { workers = defaultConcurrency }
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &fetchGroup{ tasks: make(chan func() error), workers: workers, ctx: ctx, cancel: cancel }, ctx
+}
+
+// do runs @task on a free Worker, a new Worker is only started if all Workers are busy
+// and the Limit is not reached. Blocks until a Worker accepts the Task.
+// do must only be called by a single Goroutine (and not after wait()).
+func (g *fetchGroup) do(task func() error) {
+	select {
+	case g.tasks <- task:
+		return
+	default:
+	}
+
+	if g.started < g.workers {
+		g.started++
+		g.wg.Add(1)
+
+		go g.work()
+	}
+
+	g.tasks <- task
+}
+
+// work runs the Tasks of the Group until wait() is called
+func (g *fetchGroup) work() {
+	defer g.wg.Done()
+
+	for task := range g.tasks {
+		// the remaining Tasks are skipped after the first Error (or if the Context is done)
+		if err := g.ctx.Err(); err != nil {
+			g.fail(err)
+			continue
+		}
+
+		if err := task(); err != nil { g.fail(err) }
+	}
+}
+
+// fail stores the first Error and cancels all other Tasks
+func (g *fetchGroup) fail(err error) {
+	g.errOnce.Do(func() {
+		g.err = err
+		g.cancel()
+	})
+}
+
+// wait waits for all Tasks and returns the first Error
+func (g *fetchGroup) wait() error {
+	close(g.tasks)
+	g.wg.Wait()
+	g.cancel()
+
+	return g.err
+}
@@ -0,0 +1,157 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// run with 'go test -race' to detect Races in the Scheduler and the Fetch Groups
+
+func TestSchedulerLimit(t *testing.T) {
+	const concurrency = 3
+	sched := newScheduler(concurrency)
+
+	var running, max int32
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			err := sched.do(context.Background(), func() error {
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+
+				for {
+					old := atomic.LoadInt32(&max)
+					if n <= old || atomic.CompareAndSwapInt32(&max, old, n) { break }
+				}
+
+				time.Sleep(time.Millisecond)
+				return nil
+			})
+			if err != nil { t.Error(err) }
+		}()
+	}
+	wg.Wait()
+
+	if max > concurrency { t.Errorf("expected max. %d concurrent Requests, got %d", concurrency, max) }
+}
+
+func TestSchedulerCancel(t *testing.T) {
+	sched := newScheduler(1)
+
+	// occupy the only Slot
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go sched.do(context.Background(), func() error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called := false
+	err := sched.do(ctx, func() error {
+		called = true
+		return nil
+	})
+
+	if err != context.Canceled { t.Errorf("expected %v, got %v", context.Canceled, err) }
+	if called { t.Errorf("Request was sent with a canceled Context") }
+}
+
+func TestFetchGroupWorkers(t *testing.T) {
+	const workers = 4
+	before := runtime.NumGoroutine()
+
+	group, _ := newFetchGroup(context.Background(), workers)
+
+	var done int32
+	var max int
+	for i := 0; i < 10000; i++ {
+		group.do(func() error {
+			atomic.AddInt32(&done, 1)
+			time.Sleep(time.Microsecond)
+			return nil
+		})
+
+		if n := runtime.NumGoroutine() - before; n > max { max = n }
+	}
+
+	if err := group.wait(); err != nil { t.Fatal(err) }
+
+	if done != 10000 { t.Errorf("expected 10000 Tasks, got %d", done) }
+
+	// the Context of the Group may start a Goroutine, too
+	if max > workers + 1 { t.Errorf("expected max. %d Goroutines, got %d", workers, max) }
+}
+
+func TestFetchGroupError(t *testing.T) {
+	failed := errors.New("failed")
+	group, groupCtx := newFetchGroup(context.Background(), 2)
+
+	var lock sync.Mutex			// Protects results
+	results := make(map[int]bool)
+
+	for i := 0; i < 100; i++ {
+		index := i
+
+		group.do(func() error {
+			if index == 10 { return failed }
+
+			lock.Lock()
+			results[index] = true
+			lock.Unlock()
+
+			return nil
+		})
+	}
+
+	if err := group.wait(); err != failed { t.Errorf("expected %v, got %v", failed, err) }
+	if groupCtx.Err() == nil { t.Errorf("Context of the Group is not canceled") }
+
+	// the Tasks after the Error must be skipped
+	if len(results) >= 99 { t.Errorf("expected skipped Tasks, but %d Tasks were run", len(results)) }
+}
+
+func TestFetchGroupCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sched := newScheduler(2)
+	group, groupCtx := newFetchGroup(ctx, 2)
+
+	var sent int32
+	for i := 0; i < 100; i++ {
+		if i == 5 { cancel() }
+
+		group.do(func() error {
+			return sched.do(groupCtx, func() error {
+				atomic.AddInt32(&sent, 1)
+				return nil
+			})
+		})
+	}
+
+	// a canceled Group must never report Success
+	if err := group.wait(); err != context.Canceled { t.Errorf("expected %v, got %v", context.Canceled, err) }
+	if sent > 5 { t.Errorf("expected max. 5 Requests, got %d", sent) }
+}
+
+func TestFetchGroupEmpty(t *testing.T) {
+	group, _ := newFetchGroup(context.Background(), 0)
+
+	if err := group.wait(); err != nil { t.Errorf("expected no Error, got %v", err) }
+}
@@ -0,0 +1,310 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/config"
+	"github.com/fabmation-gmbh/oima/pkg/errors"
+	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
+	"github.com/fabmation-gmbh/oima/pkg/s3"
+	"github.com/fabmation-gmbh/oima/pkg/sigstore"
+)
+
+// newSignatureStore returns the SignatureStore Provider for @store
+func newSignatureStore(store config.SigstoreConf) (sigstore.SignatureStore, error) {
+	uri := store.URI
+
+	switch {
+	case uri == "s3":
+		if !store.S3.Enabled {
+			Log.Errorf("Signature Store 's3' selected, but the S3 Component is disabled")
+			return nil, errors.NewSignatureStoreNotSupportedError(uri)
+		}
+
+		return &s3.S3Minio{ Name: store.Name, Conf: store.S3 }, nil
+	case strings.HasPrefix(uri, "file://"):
+		return &sigstore.FileStore{ Dir: strings.TrimPrefix(uri, "file://") }, nil
+	case strings.HasPrefix(uri, "http://"), strings.HasPrefix(uri, "https://"):
+		return &sigstore.HTTPStore{ URL: strings.TrimSuffix(uri, "/"), Transport: store.Transport }, nil
+	default:
+		return nil, errors.NewSignatureStoreNotSupportedError(uri)
+	}
+}
+
+// fetchSignatureData checks the Signatures (Signature Store) and
+// the Trust Data (Notary) of all Tags if the Components are enabled
+func (i *Image) fetchSignatureData(ctx context.Context) error {
+	// check Signatures in the Signature Store if one is configured
+	if i.Repository.DockerRegistry.sigStoreEnabled {
+		err := i.FetchSignatures(ctx)
+		if err != nil {
+			Log.Errorf("Error while Fetching Signatures for Image %s from Signature Store", i.Name)
+			return err
+		}
+	} else {
+		Log.Debugf("No Signature Store configured by User Config")
+	}
+
+	// check Trust Data on Notary if Notary is enabled
+	if i.Repository.DockerRegistry.notaryEnabled {
+		var img rt.BaseImage = i
+		err := i.Repository.DockerRegistry.sched.do(ctx, func() error {
+			return i.Repository.DockerRegistry.notaryCli.FetchTrustData(&img)
+		})
+		if err != nil {
+			Log.Errorf("Error while Fetching Trust Data for Image %s on Notary Server: %s", i.Name, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FetchSignatures lists the Signatures of all Tags of the Image
+// and sets tag.Signatures = "Names of all Signatures".
+// The Signatures of the Platform Manifests of an Image Index are listed, too.
+func (i *Image) FetchSignatures(ctx context.Context) error {
+	Log.Debugf("Fetching Signatures for Image %s", i.Name)
+
+	store := i.Repository.DockerRegistry.sigStore
+	imagePath := sigstore.ImagePath(i.Repository.DockerRegistry.URI, i.Name)
+
+	// every Task only writes its own Signature List, so no Lock is needed
+	group, groupCtx := newFetchGroup(ctx, i.Repository.DockerRegistry.Concurrency)
+
+	list := func(name string, digest string, signatures *[]string) {
+		group.do(func() error {
+			return i.Repository.DockerRegistry.sched.do(groupCtx, func() error {
+				result, err := store.List(groupCtx, imagePath, digest)
+				if err != nil {
+					Log.Errorf("Error while listing Signatures of '%s:%s': %s", i.Name, name, err.Error())
+					return err
+				}
+
+				*signatures = result
+				return nil
+			})
+		})
+	}
+
+	for iTag := range i.Tags {
+		tag := &i.Tags[iTag]
+
+		list(string(tag.Name), tag.ContentDigest, &tag.Signatures)
+
+		for iPlatform := range tag.Platforms {
+			p := &tag.Platforms[iPlatform]
+			list(fmt.Sprintf("%s (%s)", tag.Name, p.Platform), p.Digest, &p.Signatures)
+		}
+	}
+
+	return group.wait()
+}
+
+// signatureStores returns the writable Signature Stores which may contain Signatures of this Registry:
+// the Signature Store of the Registry and all other named Signature Stores, which are not bound to
+// another Registry. Read-only Stores are skipped, because no Signature can be deleted from them.
+// The other Stores are initialized first.
+func (r *DockerRegistry) signatureStores(ctx context.Context) ([]sigstore.SignatureStore, error) {
+	var stores []sigstore.SignatureStore
+
+	own := conf.GetSigstore(r.regConf)
+	if r.sigStoreEnabled {
+		if sigstore.ReadOnly(r.sigStore) {
+			Log.Warningf("Signature Store '%s' is read-only, its Signatures are not deleted", own.URI)
+		} else {
+			stores = append(stores, r.sigStore)
+		}
+	}
+
+	// Signature Stores of other Registries
+	bound := make(map[string]bool)
+	for _, v := range conf.GetRegistries() {
+		if v.Name != r.Name && len(v.Sigstore) > 0 { bound[v.Sigstore] = true }
+	}
+
+	for _, v := range conf.Sigstores {
+		if v.Name == own.Name && len(own.Name) > 0 { continue }
+
+		if bound[v.Name] {
+			Log.Debugf("Skipping Signature Store '%s' of another Registry", v.Name)
+			continue
+		}
+
+		storeConf := conf.GetSigstore(config.RegistryConf{ Sigstore: v.Name })
+		Log.Debugf("Initializing Signature Store '%s' (%s)", storeConf.Name, storeConf.URI)
+
+		store, err := newSignatureStore(storeConf)
+		if err == nil { err = store.Init(ctx) }
+		if err != nil {
+			Log.Errorf("Error while Initializing Signature Store '%s': %s", storeConf.Name, err.Error())
+			return nil, err
+		}
+
+		if sigstore.ReadOnly(store) {
+			Log.Debugf("Skipping read-only Signature Store '%s'", storeConf.Name)
+			continue
+		}
+
+		stores = append(stores, store)
+	}
+
+	return stores, nil
+}
+
+// ReadSignature returns the Content of the Signature @name of Tag @t
+func (i *Image) ReadSignature(ctx context.Context, t *rt.Tag, name string) ([]byte, error) {
+	return i.ReadDigestSignature(ctx, t.ContentDigest, name)
+}
+
+// ReadDigestSignature returns the Content of the Signature @name of the Manifest @digest
+// (eg. of a Platform Manifest of an Image Index)
+func (i *Image) ReadDigestSignature(ctx context.Context, digest string, name string) ([]byte, error) {
+	if !i.Repository.DockerRegistry.sigStoreEnabled { return nil, errors.NewSignatureNotFoundError() }
+
+	return i.Repository.DockerRegistry.sigStore.Read(ctx, sigstore.ImagePath(i.Repository.DockerRegistry.URI, i.Name),
+		digest, name)
+}
+
+// WriteSignature stores @data as next free Signature ('signature-N') of Tag @t
+// and returns the Name of the new Signature
+func (i *Image) WriteSignature(ctx context.Context, t *rt.Tag, data []byte) (string, error) {
+	if !i.Repository.DockerRegistry.sigStoreEnabled { return "", errors.NewSignatureStoreNotSupportedError("") }
+
+	// find first free Signature Name, containers/image stops
+	// reading Signatures at the first missing one
+	existing := make(map[string]bool)
+	for _, v := range t.Signatures { existing[v] = true }
+
+	index := 1
+	for existing[sigstore.SignatureName(index)] { index++ }
+	name := sigstore.SignatureName(index)
+
+	err := i.Repository.DockerRegistry.sigStore.Write(ctx, sigstore.ImagePath(i.Repository.DockerRegistry.URI, i.Name),
+		t.ContentDigest, name, data)
+	if err != nil {
+		Log.Errorf("Error while writing Signature '%s' of '%s:%s': %s", name, i.Name, t.Name, err.Error())
+		return "", err
+	}
+
+	// the Signatures belongs to the Digest, so update all Tags pointing to it
+	signatures := append(append([]string{}, t.Signatures...), name)
+	sigstore.SortSignatureNames(signatures)
+
+	for iTag := range i.Tags {
+		if i.Tags[iTag].ContentDigest == t.ContentDigest { i.Tags[iTag].Signatures = signatures }
+	}
+	t.Signatures = signatures
+
+	return name, nil
+}
+
+// GetReference returns the full Docker Reference of Tag @t
+// (eg. 'docker.reg.local/nginx:v1.0.0')
+func (i *Image) GetReference(t *rt.Tag) string {
+	return fmt.Sprintf("%s:%s", sigstore.ImagePath(i.Repository.DockerRegistry.URI, i.Name), t.Name)
+}
+
+// GetReferences returns the Docker References of Tag @t and of all its Aliases,
+// which are valid Identities of a Signature of the Digest of @t
+func (i *Image) GetReferences(t *rt.Tag) []string {
+	references := []string{ i.GetReference(t) }
+	for _, v := range i.Aliases(t) { references = append(references, i.GetReference(&rt.Tag{ Name: v })) }
+
+	return references
+}
+
+// FindOrphanedSignatures lists all Digests in the Signature Store of this Registry,
+// which are not referenced by any Tag and no longer exist in the Registry
+// (FetchAll() must be called first)
+func (r *DockerRegistry) FindOrphanedSignatures(ctx context.Context) ([]sigstore.DigestRef, error) {
+	if !r.sigStoreEnabled { return nil, errors.NewSignatureStoreNotSupportedError("") }
+
+	refs, err := r.sigStore.ListDigests(ctx, sigstore.PrepareRegPath(r.URI))
+	if err != nil {
+		Log.Errorf("Error while listing Signatures of Registry '%s': %s", r.URI, err.Error())
+		return nil, err
+	}
+
+	// collect all Digests which are referenced by a Tag
+	referenced := make(map[sigstore.DigestRef]bool)
+	for _, repo := range r.Repos {
+		for _, img := range repo.Images {
+			imagePath := sigstore.ImagePath(r.URI, img.Name)
+
+			// the Platform Manifests of an Image Index are referenced, too
+			for _, tag := range img.Tags {
+				for _, digest := range tag.Digests() {
+					referenced[sigstore.DigestRef{ Image: imagePath, Digest: digest }] = true
+				}
+			}
+		}
+	}
+
+	var candidates []sigstore.DigestRef
+	for _, v := range refs {
+		if !referenced[v] { candidates = append(candidates, v) }
+	}
+
+	// an untagged Manifest may still exist (eg. pulled by Digest),
+	// so only Digests which are deleted in the Registry are orphaned
+	missing := make([]bool, len(candidates))
+	regPath := sigstore.PrepareRegPath(r.URI) + "/"
+	group, groupCtx := newFetchGroup(ctx, r.Concurrency)
+
+	for index, v := range candidates {
+		index, ref := index, v
+
+		group.do(func() error {
+			image := strings.TrimPrefix(ref.Image, regPath)
+
+			authData, err := r.authData(groupCtx, repositoryScope(image, actionPull))
+			if err != nil { return err }
+			defer authData.destroy()
+
+			return r.sched.do(groupCtx, func() error {
+				exists, err := manifestExists(groupCtx, authData, image, ref.Digest, r.URI, r.Version)
+				missing[index] = !exists
+				return err
+			})
+		})
+	}
+
+	if err := group.wait(); err != nil {
+		Log.Errorf("Error while checking the Digests of Registry '%s': %s", r.URI, err.Error())
+		return nil, err
+	}
+
+	var orphans []sigstore.DigestRef
+	for index, v := range candidates {
+		if missing[index] { orphans = append(orphans, v) }
+	}
+
+	return orphans, nil
+}
+
+// ListDigestSignatures lists the Names of all Signatures of @ref
+func (r *DockerRegistry) ListDigestSignatures(ctx context.Context, ref sigstore.DigestRef) ([]string, error) {
+	if !r.sigStoreEnabled { return nil, errors.NewSignatureStoreNotSupportedError("") }
+
+	return r.sigStore.List(ctx, ref.Image, ref.Digest)
+}
+
+// DeleteDigestSignatures deletes all Signatures of @ref
+func (r *DockerRegistry) DeleteDigestSignatures(ctx context.Context, ref sigstore.DigestRef) error {
+	signatures, err := r.ListDigestSignatures(ctx, ref)
+	if err != nil { return err }
+
+	for _, name := range signatures {
+		err = r.sigStore.Delete(ctx, ref.Image, ref.Digest, name)
+		if err != nil {
+			Log.Errorf("Error while deleting Signature '%s': %s", sigstore.SignaturePath(ref.Image, ref.Digest, name), err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
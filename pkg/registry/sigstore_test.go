@@ -0,0 +1,171 @@
+package registry
+
+import (
+	"context"
+	nethttp "net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/fabmation-gmbh/oima/pkg/config"
+	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
+	"github.com/fabmation-gmbh/oima/pkg/sigstore"
+)
+
+const (
+	tagged		= "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	untagged	= "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	deleted		= "sha256:3333333333333333333333333333333333333333333333333333333333333333"
+)
+
+// newTestRegistry returns a Registry for @server with an (empty) File Signature Store
+// and the Image 'team/app' with the Tag 'latest' pointing to @tagged
+func newTestRegistry(t *testing.T, server *httptest.Server) *DockerRegistry {
+	r := &DockerRegistry{
+		Version:			V2,
+		URI:				server.URL,
+		Authentication:		Auth{ Mode: AMNone },
+		sched:				newScheduler(2),
+		httpClient:			server.Client(),
+		sigStore:			&sigstore.FileStore{ Dir: t.TempDir() },
+		sigStoreEnabled:	true,
+	}
+
+	repo := Repository{ DockerRegistry: r, Name: "team" }
+	repo.Images = []Image{{ Repository: &repo, Name: "team/app", Tags: []rt.Tag{
+		{ Name: "latest", ContentDigest: tagged },
+	}}}
+	r.Repos = []Repository{ repo }
+
+	return r
+}
+
+func TestFindOrphanedSignatures(t *testing.T) {
+	var lock sync.Mutex			// Protects heads
+	var heads []string
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, req *nethttp.Request) {
+		if req.Method != nethttp.MethodHead {
+			t.Errorf("unexpected Request %s %s", req.Method, req.URL.Path)
+		}
+		lock.Lock()
+		heads = append(heads, req.URL.Path)
+		lock.Unlock()
+
+		switch req.URL.Path {
+		case "/v2/team/app/manifests/" + untagged:
+			w.Header().Set("Docker-Content-Digest", untagged)
+			w.WriteHeader(nethttp.StatusOK)
+		default:
+			w.WriteHeader(nethttp.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	r := newTestRegistry(t, server)
+	image := sigstore.ImagePath(r.URI, "team/app")
+
+	for _, digest := range []string{ tagged, untagged, deleted } {
+		err := r.sigStore.Write(context.Background(), image, digest, sigstore.SignatureName(1), []byte("signature"))
+		if err != nil { t.Fatal(err) }
+	}
+
+	orphans, err := r.FindOrphanedSignatures(context.Background())
+	if err != nil { t.Fatal(err) }
+
+	if len(orphans) != 1 || orphans[0] != (sigstore.DigestRef{ Image: image, Digest: deleted }) {
+		t.Errorf("expected only %s to be orphaned, got %v", deleted, orphans)
+	}
+
+	// the tagged Digest is known, so only the untagged ones are checked
+	if len(heads) != 2 {
+		t.Errorf("expected 2 HEAD Requests, got %v", heads)
+	}
+}
+
+func TestFindOrphanedSignaturesError(t *testing.T) {
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, req *nethttp.Request) {
+		w.WriteHeader(nethttp.StatusForbidden)
+	}))
+	defer server.Close()
+
+	r := newTestRegistry(t, server)
+
+	err := r.sigStore.Write(context.Background(), sigstore.ImagePath(r.URI, "team/app"), deleted, sigstore.SignatureName(1), []byte("signature"))
+	if err != nil { t.Fatal(err) }
+
+	// a failed Check must never report the Digest as orphaned
+	orphans, err := r.FindOrphanedSignatures(context.Background())
+	if err == nil { t.Errorf("expected an Error, got the Orphans %v", orphans) }
+}
+
+func TestDeleteSignatureRenumbers(t *testing.T) {
+	server := httptest.NewServer(nethttp.NotFoundHandler())
+	defer server.Close()
+
+	r := newTestRegistry(t, server)
+	image := sigstore.ImagePath(r.URI, "team/app")
+	img := &r.Repos[0].Images[0]
+
+	for index := 1; index <= 3; index++ {
+		err := r.sigStore.Write(context.Background(), image, tagged, sigstore.SignatureName(index), []byte("signature"))
+		if err != nil { t.Fatal(err) }
+	}
+	img.Tags[0].Signatures = []string{ sigstore.SignatureName(1), sigstore.SignatureName(2), sigstore.SignatureName(3) }
+
+	if err := img.DeleteSignature(context.Background(), &img.Tags[0], sigstore.SignatureName(1)); err != nil { t.Fatal(err) }
+
+	// the following Signatures are moved down, so there is no Gap
+	expected := []string{ sigstore.SignatureName(1), sigstore.SignatureName(2) }
+
+	names, err := r.sigStore.List(context.Background(), image, tagged)
+	if err != nil { t.Fatal(err) }
+
+	if !reflect.DeepEqual(names, expected) { t.Errorf("expected %v in the Store, got %v", expected, names) }
+	if !reflect.DeepEqual(img.Tags[0].Signatures, expected) { t.Errorf("expected %v, got %v", expected, img.Tags[0].Signatures) }
+}
+
+func TestDeleteManifestSignatureStores(t *testing.T) {
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, req *nethttp.Request) {
+		if req.Method != nethttp.MethodDelete || req.URL.Path != "/v2/team/app/manifests/" + tagged {
+			t.Errorf("unexpected Request %s %s", req.Method, req.URL.Path)
+		}
+		w.WriteHeader(nethttp.StatusAccepted)
+	}))
+	defer server.Close()
+
+	r := newTestRegistry(t, server)
+	r.Name = "prod"
+	r.regConf = config.RegistryConf{ Name: "prod", Sigstore: "own" }
+
+	dirs := map[string]string{ "own": r.sigStore.(*sigstore.FileStore).Dir, "shared": t.TempDir(), "staging": t.TempDir() }
+
+	// the read-only Store would fail, the Store of 'staging' belongs to another Registry
+	previous := conf
+	defer func() { conf = previous }()
+	conf = config.Configuration{
+		Registries: []config.RegistryConf{ r.regConf, { Name: "staging", Sigstore: "staging" } },
+		Sigstores: []config.SigstoreConf{
+			{ Name: "own", URI: "file://" + dirs["own"] },
+			{ Name: "lookaside", URI: server.URL },
+			{ Name: "shared", URI: "file://" + dirs["shared"] },
+			{ Name: "staging", URI: "file://" + dirs["staging"] },
+		},
+	}
+
+	image := sigstore.ImagePath(r.URI, "team/app")
+	for _, dir := range dirs {
+		err := (&sigstore.FileStore{ Dir: dir }).Write(context.Background(), image, tagged, sigstore.SignatureName(1), []byte("signature"))
+		if err != nil { t.Fatal(err) }
+	}
+
+	img := &r.Repos[0].Images[0]
+	if err := img.DeleteManifest(context.Background(), &img.Tags[0]); err != nil { t.Fatal(err) }
+
+	for name, count := range map[string]int{ "own": 0, "shared": 0, "staging": 1 } {
+		names, err := (&sigstore.FileStore{ Dir: dirs[name] }).List(context.Background(), image, tagged)
+		if err != nil { t.Fatal(err) }
+
+		if len(names) != count { t.Errorf("expected %d Signatures in Store '%s', got %v", count, name, names) }
+	}
+}
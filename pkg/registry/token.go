@@ -0,0 +1,234 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/awnumar/memguard"
+	"strings"
+	"time"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/errors"
+	"github.com/fabmation-gmbh/oima/pkg/http"
+)
+
+type _TokenFlow string // Describes how Bearer Tokens are requested
+const (
+	TFAuto			_TokenFlow	= "auto"		// Use 'challenge' if the Registry sends a Bearer Challenge, otherwise 'artifactory'
+	TFChallenge		_TokenFlow	= "challenge"	// Standard Docker Token Flow (Realm of the 'WWW-Authenticate' Challenge)
+	TFArtifactory	_TokenFlow	= "artifactory"	// JFrog Artifactory Token Endpoint ('<uri>/api/docker/docker/<version>/token')
+)
+
+// Token Scopes
+const (
+	catalogScope	= "registry:catalog:*"	// Scope needed to list the Registry Catalog
+
+	actionPull		= "pull"				// Read Access to a Repository
+	actionDelete	= "delete"				// Delete Access to a Repository
+)
+
+// Default Lifetime of a Token (in Seconds) if the Token Server does not send 'expires_in'
+const defaultTokenLifetime = 60
+
+// Tokens are renewed this many Seconds before they expire,
+// so they do not expire while a Request is sent
+const tokenRenewMargin = 10
+
+// authChallenge holds the Parameters of a 'WWW-Authenticate' Challenge
+// Example: Bearer realm="https://auth.docker.io/token",service="registry.docker.io"
+type authChallenge struct {
+	Scheme		string				// Authentication Scheme (eg. 'bearer' or 'basic')
+	Realm		string				// URI of the Token Server
+	Service		string				// Name of the Service which hosts the Resource
+	Scope		string				// Scope requested by the Challenge (optional)
+}
+
+
+/// >>>>>>>>>> Functions <<<<<<<<<< ///
+
+// repositoryScope returns the Token Scope for the Repository @image with the given Actions
+// Example: repository:library/nginx:pull,delete
+func repositoryScope(image string, actions ...string) string {
+	return fmt.Sprintf("repository:%s:%s", image, strings.Join(actions, ","))
+}
+
+// parseChallenge parses the Value of a 'WWW-Authenticate' Header
+// Returns nil if the Header is empty
+func parseChallenge(header string) *authChallenge {
+	header = strings.TrimSpace(header)
+	if len(header) == 0 { return nil }
+
+	challenge := &authChallenge{}
+
+	i := strings.IndexAny(header, " \t")
+	if i < 0 { challenge.Scheme = strings.ToLower(header); return challenge }
+
+	challenge.Scheme = strings.ToLower(header[:i])
+	params := header[i+1:]
+
+	// Parameters are separated by ',', but Values may be quoted and contain ','
+	for len(params) > 0 {
+		params = strings.TrimLeft(params, " \t,")
+
+		eq := strings.Index(params, "=")
+		if eq < 0 { break }
+
+		key := strings.ToLower(strings.TrimSpace(params[:eq]))
+		params = strings.TrimLeft(params[eq+1:], " \t")
+
+		var value string
+		if strings.HasPrefix(params, "\"") {
+			// quoted String (RFC 7230), '\' escapes the next Character (eg. '\"' or '\\')
+			var buf strings.Builder
+			end := 1
+			for end < len(params) && params[end] != '"' {
+				if params[end] == '\\' && end+1 < len(params) { end++ }
+
+				buf.WriteByte(params[end])
+				end++
+			}
+
+			value = buf.String()
+			if end < len(params) { end++ }
+			params = params[end:]
+		} else {
+			end := strings.Index(params, ",")
+			if end < 0 { end = len(params) }
+
+			value = strings.TrimSpace(params[:end])
+			params = params[end:]
+		}
+
+		switch key {
+		case "realm":	challenge.Realm = value
+		case "service":	challenge.Service = value
+		case "scope":	challenge.Scope = value
+		}
+	}
+
+	return challenge
+}
+
+// bearerChallenge returns true if the Registry requested a Bearer Token with a valid Realm
+func (c *authChallenge) bearerChallenge() bool {
+	return c != nil && c.Scheme == "bearer" && len(c.Realm) > 0
+}
+
+// getToken returns the (opened) Bearer Token for @scope.
+// Tokens are cached per Scope and renewed shortly before they expire.
+// Only Requests of the same Scope wait for each other, Tokens of
+// different Scopes are requested concurrently.
+func (c *Credential) getToken(ctx context.Context, scope string) (*memguard.LockedBuffer, error) {
+	// the Artifactory Token is not scoped
+	if c.tokenFlow != TFChallenge { scope = "" }
+
+	c.tokenLock.Lock()
+	if c.tokens == nil { c.tokens = make(map[string]*scopeToken) }
+
+	cached, ok := c.tokens[scope]
+	if !ok {
+		cached = &scopeToken{}
+		c.tokens[scope] = cached
+	}
+	c.tokenLock.Unlock()
+
+	cached.lock.Lock()
+	defer cached.lock.Unlock()
+
+	token := cached.token
+	if token == nil || token.ExpiresOn - tokenRenewMargin <= time.Now().Unix() {
+		if token != nil {
+			Log.Debugf("Re-Newing BearerToken for Scope '%s' because it expires on %s", scope, time.Unix(token.ExpiresOn, 0))
+		}
+
+		var err error
+		token, err = c.getBearerToken(ctx, scope)
+		if err != nil { return nil, err }
+
+		cached.token = token
+	}
+
+	return token.BearerToken.Open()
+}
+
+// getBearerToken requests a new Bearer Token for @scope from the Token Server
+func (c *Credential) getBearerToken(ctx context.Context, scope string) (*BearerToken, error) {
+	client := http.NewRestyClient(c.auth.dockerRegistry.httpClient)
+	client.SetHeader("Docker-Distribution-Api-Version", "registry/2.0")
+
+	// only send Credentials if the User configured them
+	// (anonymous Tokens are possible in the 'challenge' Flow)
+	if c.auth.Required {
+		password, err := c.Password.Open()
+		if err != nil { return nil, err }
+		defer password.Destroy()
+
+		client.SetBasicAuth(c.Username, password.String())
+	}
+
+	var uri string
+	req := client.R()
+
+	if c.tokenFlow == TFChallenge {
+		uri = c.challenge.Realm
+
+		if len(c.challenge.Service) > 0 { req.SetQueryParam("service", c.challenge.Service) }
+		if len(scope) > 0 { req.SetQueryParam("scope", scope) }
+	} else {
+		uri = fmt.Sprintf("%s/api/docker/docker/%s/token", c.auth.dockerRegistry.URI, c.auth.dockerRegistry.Version)
+	}
+
+	Log.Debugf("Requesting Bearer Token for Scope '%s' from %s", scope, uri)
+
+	resp, err := http.Retry.Get(ctx, req, uri)
+	if err != nil {
+		Log.Criticalf("Error while getting Auth. Token: %s", err.Error())
+		return nil, err
+	}
+
+	if resp.StatusCode() == 401 || resp.StatusCode() == 403 {
+		return nil, errors.NewAuthenticationError(c.Username, resp.StatusCode())
+	}
+
+	if resp.StatusCode() != 200 {
+		Log.Debugf("Response: %s", resp.Body())
+		return nil, responseError(uri, resp)
+	}
+
+	type _token struct {
+		// The BearerToken is needed to communicate with the Registry API
+		BearerToken		string				`json:"token"`
+
+		// Some Token Servers (OAuth2 compatible) send the Token as 'access_token'
+		AccessToken		string				`json:"access_token"`
+
+		// Seconds until the Token expires
+		ExpiresIn		int64				`json:"expires_in"`
+	}
+	tokenData := _token{}
+
+	err = json.Unmarshal(resp.Body(), &tokenData)
+	if err != nil {
+		Log.Debugf("Response: %s", resp.Body())
+		Log.Errorf("Error while marshaling Response: %s", err.Error())
+		return nil, err
+	}
+
+	if len(tokenData.BearerToken) == 0 { tokenData.BearerToken = tokenData.AccessToken }
+	if len(tokenData.BearerToken) == 0 {
+		return nil, errors.NewRegistryRequestErrorMsg(fmt.Sprintf("Token Server '%s' did not return a Token", uri))
+	}
+
+	if tokenData.ExpiresIn <= 0 { tokenData.ExpiresIn = defaultTokenLifetime }
+
+	// convert Seconds into Unix Timestamp
+	token := &BearerToken{
+		BearerToken: memguard.NewEnclave([]byte(tokenData.BearerToken)),
+		ExpiresOn:   time.Now().Unix() + tokenData.ExpiresIn,
+	}
+
+	Log.Debugf("Bearer Token for Scope '%s' Expires On %d (%s)", scope, token.ExpiresOn, time.Unix(token.ExpiresOn, 0))
+
+	return token, nil
+}
@@ -0,0 +1,164 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseChallenge(t *testing.T) {
+	tests := []struct {
+		header		string
+		expected	*authChallenge
+	}{
+		{ "", nil },
+		{ "Basic", &authChallenge{ Scheme: "basic" } },
+		{ `Basic realm="Registry Realm"`, &authChallenge{ Scheme: "basic", Realm: "Registry Realm" } },
+		{
+			`Bearer realm="https://auth.docker.io/token",service="registry.docker.io"`,
+			&authChallenge{ Scheme: "bearer", Realm: "https://auth.docker.io/token", Service: "registry.docker.io" },
+		},
+		{
+			// quoted Values may contain ','
+			`Bearer realm="https://auth/token",service="reg",scope="repository:team/app:pull,push"`,
+			&authChallenge{ Scheme: "bearer", Realm: "https://auth/token", Service: "reg", Scope: "repository:team/app:pull,push" },
+		},
+		{
+			// unquoted Values, Whitespace and upper case Keys
+			`Bearer Realm=https://auth/token , Service=reg`,
+			&authChallenge{ Scheme: "bearer", Realm: "https://auth/token", Service: "reg" },
+		},
+		{
+			`Bearer realm="https://auth/\"quoted\"/token",service="reg"`,
+			&authChallenge{ Scheme: "bearer", Realm: `https://auth/"quoted"/token`, Service: "reg" },
+		},
+		{
+			`Bearer realm="https://auth/token",service="domain\\registry"`,
+			&authChallenge{ Scheme: "bearer", Realm: "https://auth/token", Service: `domain\registry` },
+		},
+		{
+			// '\' only escapes the next Character
+			`Bearer realm="a\b\\\"c",service="reg"`,
+			&authChallenge{ Scheme: "bearer", Realm: `ab\"c`, Service: "reg" },
+		},
+		{
+			// unterminated quoted String
+			`Bearer realm="https://auth/token`,
+			&authChallenge{ Scheme: "bearer", Realm: "https://auth/token" },
+		},
+	}
+
+	for _, test := range tests {
+		challenge := parseChallenge(test.header)
+
+		switch {
+		case test.expected == nil && challenge != nil:
+			t.Errorf("parseChallenge(%q): expected nil, got %+v", test.header, *challenge)
+		case test.expected != nil && (challenge == nil || *challenge != *test.expected):
+			t.Errorf("parseChallenge(%q): expected %+v, got %+v", test.header, *test.expected, challenge)
+		}
+	}
+}
+
+// newTokenCredential returns Credentials which request (anonymous) Tokens from @server
+func newTokenCredential(server *httptest.Server) *Credential {
+	r := &DockerRegistry{ URI: server.URL, Version: V2, httpClient: server.Client() }
+
+	return &Credential{
+		auth:		&Auth{ dockerRegistry: r, Mode: AMBearer },
+		challenge:	&authChallenge{ Scheme: "bearer", Realm: server.URL + "/token", Service: "registry" },
+		tokenFlow:	TFChallenge,
+	}
+}
+
+func TestGetTokenScopesConcurrent(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, req *nethttp.Request) {
+		scope := req.URL.Query().Get("scope")
+
+		// the Token Server is slow for a single Scope
+		if scope == repositoryScope("slow", actionPull) { <-release }
+
+		fmt.Fprintf(w, `{"token":"%s","expires_in":300}`, scope)
+	}))
+	defer server.Close()
+
+	// the slow Request must be released before the Server is closed
+	var releaseOnce sync.Once
+	defer releaseOnce.Do(func() { close(release) })
+
+	c := newTokenCredential(server)
+
+	slow := make(chan error, 1)
+	go func() {
+		token, err := c.getToken(context.Background(), repositoryScope("slow", actionPull))
+		if err == nil { token.Destroy() }
+		slow <- err
+	}()
+
+	// the Token of another Scope must not wait for the slow Scope
+	fast := make(chan error, 1)
+	go func() {
+		// make sure the slow Request is running
+		time.Sleep(50 * time.Millisecond)
+
+		token, err := c.getToken(context.Background(), repositoryScope("fast", actionPull))
+		if err == nil {
+			if token.String() != repositoryScope("fast", actionPull) { err = fmt.Errorf("unexpected Token '%s'", token.String()) }
+			token.Destroy()
+		}
+		fast <- err
+	}()
+
+	select {
+	case err := <-fast:
+		if err != nil { t.Fatal(err) }
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Token Request of another Scope is blocked by a slow Token Request")
+	}
+
+	releaseOnce.Do(func() { close(release) })
+	if err := <-slow; err != nil { t.Fatal(err) }
+}
+
+func TestGetTokenRenew(t *testing.T) {
+	var lock sync.Mutex			// Protects requests
+	requests := 0
+
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, req *nethttp.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		requests++
+		fmt.Fprintf(w, `{"token":"token-%d","expires_in":300}`, requests)
+	}))
+	defer server.Close()
+
+	c := newTokenCredential(server)
+	scope := repositoryScope("team/app", actionPull)
+
+	get := func() string {
+		token, err := c.getToken(context.Background(), scope)
+		if err != nil { t.Fatal(err) }
+		defer token.Destroy()
+
+		// String() points to the Buffer, which is wiped by Destroy()
+		return string(token.Bytes())
+	}
+
+	// valid Tokens are cached
+	if first, second := get(), get(); first != "token-1" || second != "token-1" {
+		t.Errorf("expected the cached Token 'token-1', got '%s' and '%s'", first, second)
+	}
+
+	// Tokens are renewed shortly before they expire
+	c.tokens[scope].token.ExpiresOn = time.Now().Unix() + tokenRenewMargin - 1
+
+	if token := get(); token != "token-2" {
+		t.Errorf("expected the renewed Token 'token-2', got '%s'", token)
+	}
+}
@@ -2,28 +2,15 @@ package s3
 
 import (
 	"github.com/awnumar/memguard"
-	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
-)
-
-type S3 interface {
-	// Initializes the S3 Datatypes
-	InitS3() error
-
-	// Fetches Signatures of all Tags of an Image and sets the Bool
-	// in image.Tags[n].S3SignFound = "Signature Found?"
-	FetchSignatures(image *rt.BaseImage)		error
-
-	// Deletes a Signature of the specific Tag from the S3-Server
-	// @ctxPath	describes the Full converted Path of registry+image+tag_info
-	//			do **NOT** add any annotations like '/signature-1'!!
-	DeleteSignature(ctxPath string, tag *rt.Tag)
-}
 
+	"github.com/fabmation-gmbh/oima/pkg/config"
+)
 
 //noinspection GoNameStartsWithPackageName
 type S3Auth interface {
 	// Initializes the required Datatypes for Authentication
-	InitAuth()					error
+	// (the Credentials are taken from the CredStore, see config.CredentialName())
+	InitAuth(conf config.S3Conf, owner string)	error
 
 
 	/// >>>>> AccessKeyID & SecretAccessKeyID <<<<<
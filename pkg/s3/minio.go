@@ -1,170 +1,242 @@
 package s3
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"github.com/awnumar/memguard"
 	"github.com/minio/minio-go"
+	"io/ioutil"
 	"strings"
 
 	"github.com/fabmation-gmbh/oima/internal"
 	. "github.com/fabmation-gmbh/oima/internal/log"
 	"github.com/fabmation-gmbh/oima/pkg/config"
 	"github.com/fabmation-gmbh/oima/pkg/errors"
-	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
+	"github.com/fabmation-gmbh/oima/pkg/http"
+	"github.com/fabmation-gmbh/oima/pkg/sigstore"
 )
 
-var conf config.Configuration
-
-
-func (s *S3Minio) InitS3() error {
-	// initialize Config
-	conf = internal.GetConfig()
-
+func (s *S3Minio) Init(ctx context.Context) error {
 	// check if S3 is enabled
-	if !conf.S3.Enabled {
-		Log.Fatalf("[Internal Error] Calling initS3() but S3 Component is disabled!")
-		memguard.SafeExit(1)
-	}
-
-	// TODO: implement provider
+	if !s.Conf.Enabled { return errors.NewComponentDisabledError("s3") }
 
 	// initialize Auth
 	s.Auth = &S3AuthMinio{}
-	err := s.Auth.InitAuth()
+	err := s.Auth.InitAuth(s.Conf, s.Name)
 	if err != nil {
-		Log.Fatalf("Error while initializing MinIO S3 Authentication: %s", err.Error())
+		Log.Errorf("Error while initializing MinIO S3 Authentication: %s", err.Error())
 		return err
 	}
 
-	return nil
-}
+	// open credentials
+	accessKeyID, err := s.Auth.GetAccessKeyID().Open()
+	if err != nil { return err }
+	defer accessKeyID.Destroy()
 
-// SignatureExists() checks if all Signatures of all
-func (s *S3Minio) FetchSignatures(image *rt.BaseImage) error {
-	Log.Debugf("Fetching Signatures for Image %s", (*image).GetName())
-
-	//// check if @image is Empty
-	//if len((*image).GetTags()) == 0 {
-	//	Log.Fatal("Requested Image is Empty (empty Struct)!")
-	//	memguard.SafeExit(1)
-	//}
-	tags := (*image).GetTags()
-
-	// create object Path Prefix
-	registryName := PrepareRegPath((*image).GetRegistryURI())
-	objPathPrefix := fmt.Sprintf("%s/%s@", registryName, (*image).GetName())
-
-	doneCh := make(chan struct{})
-	defer close(doneCh)
-
-	// check Tag Objects at the S3 MinIO Server
-	for iTag, _ := range tags {
-		objName := fmt.Sprintf("%s%s/signature-1", objPathPrefix, strings.ReplaceAll(tags[iTag].ContentDigest, ":", "="))
-
-		_, err := internal.S3Client.StatObject(s.Auth.BucketName, objName, minio.StatObjectOptions{})
-		if err != nil {
-			errResponse := minio.ToErrorResponse(err)
-			var errMsg string
-			var signNotFound = false		// if true than it does not print a Message or Exit the Application
-
-			if errResponse.Code == "AccessDenied" {
-				errMsg = fmt.Sprintf("S3 Server returned %s. You have not the Permissions to stat the File!",
-										errResponse.Code)
-			} else if errResponse.Code == "NoSuchBucket" {
-				errMsg = fmt.Sprintf("S3 Server returned %s. A Bucket with the Name '%s' wasn't found!",
-					errResponse.Code, s.Auth.BucketName)
-			} else if errResponse.Code == "InvalidBucketName" {
-				errMsg = fmt.Sprintf("S3 Server returned %s. The Bucket Name (%s) contains invalid chars!",
-					errResponse.Code, s.Auth.BucketName)
-			} else if errResponse.Code == "NoSuchKey" {
-				// Signature File does not exists
-				signNotFound = true
-				tags[iTag].S3SignFound = false
-			} else {
-				errMsg = fmt.Sprintf("Unknown Error while getting Object for Image '%s@%s': %s",
-										(*image).GetName(), tags[iTag].ContentDigest, err.Error())
-			}
+	secretAccessKeyID, err := s.Auth.GetSecretAccessKeyID().Open()
+	if err != nil { return err }
+	defer secretAccessKeyID.Destroy()
 
-			if !signNotFound {
-				Log.Critical(errMsg)
-				memguard.SafeExit(1)
-			}
-		} else { tags[iTag].S3SignFound = true }
-		//Log.Debugf("++>> Object Path: %s", objName)
+	// Requests are retried by the shared Retry Policy (see http.Retry),
+	// so the MinIO Client sends every Request only once
+	minio.MaxRetry = 1
+
+	// initialize Minio Client object, it keeps the Keys after the Buffers are
+	// destroyed and String() points to the Buffer, so the Keys are copied
+	s.client, err = minio.New(s.Auth.GetEndpoint(), string(accessKeyID.Bytes()), string(secretAccessKeyID.Bytes()), s.Auth.TLSEnabled())
+	if err != nil {
+		Log.Errorf("Error while initializing MinIO Client: %s", err.Error())
+		return err
+	}
+
+	// TLS, Proxy and Timeouts of the S3 Server
+	transport, err := http.NewTransport(s.Conf.Transport)
+	if err != nil {
+		Log.Errorf("Invalid 'transport' Configuration of the S3 Server: %s", err.Error())
+		return err
 	}
+	s.client.SetCustomTransport(transport)
 
-	// update Tags from Image
-	(*image).SetTags(tags)
+	Log.Debugf("MinIO S3 Client initialization finished")
 
 	return nil
 }
 
-func (s *S3Minio) DeleteSignature(ctxPath string, tag *rt.Tag) {
-	err := internal.S3Client.RemoveObject(s.Auth.BucketName, fmt.Sprintf("%s/signature-1", ctxPath))
-	if err != nil {
-		Log.Fatalf("Error while trying to delete Signature from '%s': %s", ctxPath, err.Error())
-		memguard.SafeExit(1)
-	}
+func (s *S3Minio) Exists(ctx context.Context, image string, digest string) (bool, error) {
+	signatures, err := s.List(ctx, image, digest)
+	if err != nil { return false, err }
+
+	return len(signatures) > 0, nil
 }
 
+func (s *S3Minio) List(ctx context.Context, image string, digest string) ([]string, error) {
+	prefix := fmt.Sprintf("%s/", sigstore.DigestPath(image, digest))
 
-func (auth *S3AuthMinio) InitAuth() error {
+	var signatures []string
 
-	// check if S3 is enabled
-	if !conf.S3.Enabled {
-		Log.Fatalf("[Internal Error] Calling initS3() but S3 Component is disabled!")
-		memguard.SafeExit(1)
-	}
+	err := http.Retry.Do(ctx, func() error {
+		doneCh := make(chan struct{})
+		defer close(doneCh)
 
-	_accessKeyID, err := internal.Cred.GetCredential("s3_accessKeyID")
-	if err != nil {
-		if _, ok := err.(*errors.CredentialNotExistsError); ok {
-			Log.Fatalf("Demanded Credential (key: %s) does not exists in CredStore: %s",
-						"s3_accessKeyID", err.Error())
-			memguard.SafeExit(1)
+		signatures = nil
+		for obj := range s.client.ListObjectsV2(s.Auth.BucketName, prefix, false, doneCh) {
+			if obj.Err != nil { return s.convertError(obj.Err, prefix) }
+			if err := ctx.Err(); err != nil { return err }
+
+			name := strings.TrimPrefix(obj.Key, prefix)
+			if sigstore.IsSignatureName(name) { signatures = append(signatures, name) }
 		}
 
-		Log.Criticalf("Error while getting encrypted Credential")
-		memguard.SafeExit(1)
-	}
-	auth.accessKeyID = _accessKeyID
+		return nil
+	})
+	if err != nil { return nil, err }
 
-	_secretAccessKeyID, err := internal.Cred.GetCredential("s3_secretAccessKeyID")
-	if err != nil {
-		if _, ok := err.(*errors.CredentialNotExistsError); ok {
-			Log.Fatalf("Demanded Credential (key: %s) does not exists in CredStore: %s",
-				"s3_secretAccessKeyID", err.Error())
-			memguard.SafeExit(1)
+	sigstore.SortSignatureNames(signatures)
+
+	return signatures, nil
+}
+
+func (s *S3Minio) Read(ctx context.Context, image string, digest string, name string) ([]byte, error) {
+	objName := sigstore.SignaturePath(image, digest, name)
+
+	var data []byte
+
+	err := http.Retry.Do(ctx, func() error {
+		obj, err := s.client.GetObjectWithContext(ctx, s.Auth.BucketName, objName, minio.GetObjectOptions{})
+		if err != nil { return s.convertError(err, objName) }
+		defer obj.Close()
+
+		data, err = ioutil.ReadAll(obj)
+		if err != nil { return s.convertError(err, objName) }
+
+		return nil
+	})
+	if err != nil { return nil, err }
+
+	return data, nil
+}
+
+func (s *S3Minio) Write(ctx context.Context, image string, digest string, name string, data []byte) error {
+	objName := sigstore.SignaturePath(image, digest, name)
+
+	return http.Retry.Do(ctx, func() error {
+		_, err := s.client.PutObjectWithContext(ctx, s.Auth.BucketName, objName, bytes.NewReader(data), int64(len(data)),
+			minio.PutObjectOptions{ContentType: "application/octet-stream"})
+		if err != nil { return s.convertError(err, objName) }
+
+		return nil
+	})
+}
+
+func (s *S3Minio) Delete(ctx context.Context, image string, digest string, name string) error {
+	objName := sigstore.SignaturePath(image, digest, name)
+
+	return http.Retry.Do(ctx, func() error {
+		// the MinIO Client can not cancel the Removal of an Object
+		if err := ctx.Err(); err != nil { return err }
+
+		err := s.client.RemoveObject(s.Auth.BucketName, objName)
+		if err != nil { return s.convertError(err, objName) }
+
+		return nil
+	})
+}
+
+func (s *S3Minio) ListDigests(ctx context.Context, prefix string) ([]sigstore.DigestRef, error) {
+	var refs []sigstore.DigestRef
+
+	err := http.Retry.Do(ctx, func() error {
+		doneCh := make(chan struct{})
+		defer close(doneCh)
+
+		refs = nil
+		found := make(map[sigstore.DigestRef]bool)
+
+		for obj := range s.client.ListObjectsV2(s.Auth.BucketName, fmt.Sprintf("%s/", prefix), true, doneCh) {
+			if obj.Err != nil { return s.convertError(obj.Err, prefix) }
+			if err := ctx.Err(); err != nil { return err }
+
+			image, digest, _, ok := sigstore.ParseSignaturePath(obj.Key)
+			if !ok { continue }
+
+			ref := sigstore.DigestRef{ Image: image, Digest: digest }
+			if !found[ref] {
+				found[ref] = true
+				refs = append(refs, ref)
+			}
 		}
 
-		Log.Criticalf("Error while getting encrypted Credential")
-		memguard.SafeExit(1)
+		return nil
+	})
+	if err != nil { return nil, err }
+
+	return refs, nil
+}
+
+// convertError logs a meaningful Message for the MinIO Error @err
+// and converts it into the matching Error of the errors Package
+func (s *S3Minio) convertError(err error, objName string) error {
+	errResponse := minio.ToErrorResponse(err)
+
+	switch errResponse.Code {
+	case "AccessDenied":
+		Log.Criticalf("S3 Server returned %s. You have not the Permissions to access '%s'!",
+			errResponse.Code, objName)
+		return errors.NewS3AccessDeniedError(objName)
+	case "NoSuchBucket":
+		Log.Criticalf("S3 Server returned %s. A Bucket with the Name '%s' wasn't found!",
+			errResponse.Code, s.Auth.BucketName)
+		return errors.NewS3BucketNotFoundError(s.Auth.BucketName)
+	case "InvalidBucketName":
+		Log.Criticalf("S3 Server returned %s. The Bucket Name (%s) contains invalid chars!",
+			errResponse.Code, s.Auth.BucketName)
+		return errors.NewS3BucketNotFoundErrorMsg(fmt.Sprintf("The S3 Bucket Name '%s' is invalid", s.Auth.BucketName))
+	case "NoSuchKey":
+		// Signature File does not exists
+		return errors.NewSignatureNotFoundError()
+	case "SlowDown":
+		Log.Debugf("S3 Server returned %s while accessing '%s'", errResponse.Code, objName)
+		return errors.NewRateLimitErrorMsg(fmt.Sprintf("S3 Server rate limited the Request '%s'", objName))
+	case "ServiceUnavailable", "InternalError", "RequestTimeout":
+		Log.Debugf("S3 Server returned %s while accessing '%s'", errResponse.Code, objName)
+		return errors.NewServiceUnavailableErrorMsg(fmt.Sprintf("S3 Server returned %s for '%s'", errResponse.Code, objName))
+	default:
+		Log.Criticalf("Unknown Error while accessing Object '%s': %s", objName, err.Error())
 	}
-	auth.secretAccessKeyID = _secretAccessKeyID
 
-	// open credentials
-	accessKeyID, err := auth.accessKeyID.Open()
-	if err != nil { memguard.SafePanic(err) }
-	defer accessKeyID.Destroy()
+	return err
+}
 
-	secretAccessKeyID, err := auth.secretAccessKeyID.Open()
-	if err != nil { memguard.SafePanic(err) }
-	defer secretAccessKeyID.Destroy()
 
+func (auth *S3AuthMinio) InitAuth(conf config.S3Conf, owner string) error {
 
-	// initialize objects of Struct
-	auth.Endpoint = conf.S3.Endpoint
-	auth.UseSSL = conf.S3.UseSSL
-	auth.BucketName = conf.S3.BucketName
+	// check if S3 is enabled
+	if !conf.Enabled { return errors.NewComponentDisabledError("s3") }
 
-	// initialize Minio Client object
-	internal.S3Client, err = minio.New(auth.Endpoint, conf.S3.AccessKeyID, conf.S3.SecretAccessKey, auth.UseSSL)
+	accessKeyName := config.CredentialName("s3_accessKeyID", owner)
+	secretKeyName := config.CredentialName("s3_secretAccessKeyID", owner)
+
+	_accessKeyID, err := internal.Cred.GetCredential(accessKeyName)
 	if err != nil {
-		Log.Fatalf("Error while initializing MinIO Client: %s", err.Error())
-		memguard.SafeExit(1)
+		Log.Errorf("Demanded Credential (key: %s) does not exists in CredStore: %s", accessKeyName, err.Error())
+		return err
 	}
+	auth.accessKeyID = _accessKeyID
 
-	Log.Debugf("MinIO S3 Client initialization finished")
+	_secretAccessKeyID, err := internal.Cred.GetCredential(secretKeyName)
+	if err != nil {
+		Log.Errorf("Demanded Credential (key: %s) does not exists in CredStore: %s", secretKeyName, err.Error())
+		return err
+	}
+	auth.secretAccessKeyID = _secretAccessKeyID
+
+	// initialize objects of Struct
+	auth.Endpoint = conf.Endpoint
+	auth.UseSSL = conf.UseSSL
+	auth.BucketName = conf.BucketName
+
+	Log.Debugf("MinIO S3 Authentication initialization finished")
 
 	return nil
 }
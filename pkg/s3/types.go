@@ -2,11 +2,19 @@ package s3
 
 import (
 	"github.com/awnumar/memguard"
+	"github.com/minio/minio-go"
+
+	"github.com/fabmation-gmbh/oima/pkg/config"
 )
 
+// S3Minio is the MinIO Implementation of the SignatureStore
 //noinspection GoNameStartsWithPackageName
 type S3Minio struct {
+	Name		string				// Name of the Signature Store (empty for the global 's3' Block)
+	Conf		config.S3Conf		// S3-Server Configuration
 	Auth		*S3AuthMinio
+
+	client		*minio.Client		// MinIO Client used to check and edit Signatures
 }
 
 
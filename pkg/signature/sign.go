@@ -0,0 +1,97 @@
+package signature
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/packet"
+
+	"github.com/fabmation-gmbh/oima/pkg/errors"
+)
+
+// LoadSecretKeyring reads all (armored or binary) Secret Keys from @path
+func LoadSecretKeyring(path string) (openpgp.EntityList, error) {
+	keyring, err := LoadKeyring([]string{path})
+	if err != nil { return nil, err }
+
+	var secretKeys openpgp.EntityList
+	for _, v := range keyring {
+		if v.PrivateKey != nil { secretKeys = append(secretKeys, v) }
+	}
+
+	return secretKeys, nil
+}
+
+// FindKey returns the Entity of @keyring with the Key ID
+// or Fingerprint @keyID (eg. '3F1A2B4C5D6E7F80' or '5D6E7F80')
+func FindKey(keyring openpgp.EntityList, keyID string) (*openpgp.Entity, error) {
+	keyID = strings.ToUpper(strings.TrimPrefix(strings.ReplaceAll(keyID, " ", ""), "0x"))
+
+	for _, v := range keyring {
+		fingerprint := fmt.Sprintf("%X", v.PrimaryKey.Fingerprint)
+		if len(keyID) >= 8 && strings.HasSuffix(fingerprint, keyID) { return v, nil }
+	}
+
+	return nil, errors.NewKeyNotFoundError(keyID)
+}
+
+// NewPayload creates the simple signing Payload for the Image @reference
+// (eg. 'docker.reg.local/nginx:v1.0.0') with the Manifest Digest @digest
+func NewPayload(reference string, digest string, creator string) ([]byte, error) {
+	p := payload{
+		Critical: critical{
+			Identity: identity{ DockerReference: reference },
+			Image:    imageData{ DockerManifestDigest: digest },
+			Type:     signatureType,
+		},
+		Optional: optional{
+			Creator:   creator,
+			Timestamp: time.Now().Unix(),
+		},
+	}
+
+	return json.Marshal(p)
+}
+
+// Sign signs @payload with the (decrypted) Key @signer and
+// returns the signed OpenPGP Message, which can be stored in the 'sigstore'
+func Sign(payload []byte, signer *openpgp.Entity) ([]byte, error) {
+	var buf bytes.Buffer
+	config := &packet.Config{ DefaultHash: crypto.SHA256 }
+
+	w, err := openpgp.Sign(&buf, signer, nil, config)
+	if err != nil { return nil, err }
+
+	_, err = w.Write(payload)
+	if err != nil { return nil, err }
+
+	err = w.Close()
+	if err != nil { return nil, err }
+
+	return buf.Bytes(), nil
+}
+
+// DecryptKey decrypts the private Key (and all Sub-Keys) of @entity with @passphrase
+func DecryptKey(entity *openpgp.Entity, passphrase []byte) error {
+	if entity.PrivateKey != nil && entity.PrivateKey.Encrypted {
+		if err := entity.PrivateKey.Decrypt(passphrase); err != nil { return err }
+	}
+
+	for _, v := range entity.Subkeys {
+		if v.PrivateKey != nil && v.PrivateKey.Encrypted {
+			if err := v.PrivateKey.Decrypt(passphrase); err != nil { return err }
+		}
+	}
+
+	return nil
+}
+
+// IsEncrypted returns true if the private Key of @entity is encrypted
+func IsEncrypted(entity *openpgp.Entity) bool {
+	return entity.PrivateKey != nil && entity.PrivateKey.Encrypted
+}
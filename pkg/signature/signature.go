@@ -0,0 +1,64 @@
+// Signature Package parses the simple signing Signatures (used by
+// containers/image, CRI-O, Podman, ...) stored in a 'sigstore'
+package signature
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"golang.org/x/crypto/openpgp"
+
+	"github.com/fabmation-gmbh/oima/pkg/errors"
+)
+
+// Parse unwraps the OpenPGP signed Message @data and decodes the contained Payload.
+// ATTENTION: Parse does **NOT** verify the Signature!
+func Parse(data []byte) (*Signature, error) {
+	// an empty Keyring is enough to read the (unverified) Message
+	md, err := openpgp.ReadMessage(bytes.NewReader(data), openpgp.EntityList{}, nil, nil)
+	if err != nil { return nil, errors.NewInvalidSignatureErrorMsg(fmt.Sprintf("Invalid OpenPGP Message: %s", err.Error())) }
+
+	if !md.IsSigned { return nil, errors.NewInvalidSignatureErrorMsg("OpenPGP Message is not signed") }
+
+	body, err := ioutil.ReadAll(md.UnverifiedBody)
+	if err != nil { return nil, errors.NewInvalidSignatureErrorMsg(fmt.Sprintf("Invalid OpenPGP Message: %s", err.Error())) }
+
+	sig, err := parsePayload(body)
+	if err != nil { return nil, err }
+
+	sig.KeyID = FormatKeyID(md.SignedByKeyId)
+
+	return sig, nil
+}
+
+// FormatKeyID returns the (long) hexadecimal Representation of an OpenPGP Key ID
+func FormatKeyID(keyID uint64) string { return fmt.Sprintf("%016X", keyID) }
+
+
+/// >>>>> Internal Functions <<<<<
+
+// parsePayload decodes the JSON Payload of a Signature
+func parsePayload(body []byte) (*Signature, error) {
+	var p payload
+
+	err := json.Unmarshal(body, &p)
+	if err != nil { return nil, errors.NewInvalidSignatureErrorMsg(fmt.Sprintf("Invalid Payload: %s", err.Error())) }
+
+	if p.Critical.Type != signatureType {
+		return nil, errors.NewInvalidSignatureErrorMsg(fmt.Sprintf("Unsupported Signature Type '%s'", p.Critical.Type))
+	}
+
+	sig := &Signature{
+		ManifestDigest:  p.Critical.Image.DockerManifestDigest,
+		DockerReference: p.Critical.Identity.DockerReference,
+		Creator:         p.Optional.Creator,
+		Payload:         body,
+	}
+
+	if p.Optional.Timestamp != 0 { sig.Timestamp = time.Unix(p.Optional.Timestamp, 0) }
+
+	return sig, nil
+}
@@ -0,0 +1,47 @@
+package signature
+
+import (
+	"time"
+)
+
+// Type of a simple signing Signature (see containers/image)
+const signatureType = "atomic container signature"
+
+// Signature contains the Informations of a (simple signing) Signature
+type Signature struct {
+	ManifestDigest		string			// Docker Content Digest of the signed Manifest
+	DockerReference		string			// Signed Docker Reference (eg. 'docker.reg.local/nginx:v1.0.0')
+	Creator				string			// Tool that created the Signature (optional)
+	Timestamp			time.Time		// Creation Time of the Signature (optional)
+	KeyID				string			// ID of the Key that signed the Payload (eg. '3F1A2B4C5D6E7F80')
+
+	Payload				[]byte			// The raw (signed) JSON Payload
+}
+
+
+/// >>>>> Payload <<<<<
+
+// payload is the JSON Document that gets signed
+type payload struct {
+	Critical	critical		`json:"critical"`
+	Optional	optional		`json:"optional"`
+}
+
+type critical struct {
+	Identity	identity		`json:"identity"`
+	Image		imageData		`json:"image"`
+	Type		string			`json:"type"`
+}
+
+type identity struct {
+	DockerReference		string	`json:"docker-reference"`
+}
+
+type imageData struct {
+	DockerManifestDigest	string	`json:"docker-manifest-digest"`
+}
+
+type optional struct {
+	Creator		string			`json:"creator,omitempty"`
+	Timestamp	int64			`json:"timestamp,omitempty"`
+}
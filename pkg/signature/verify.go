@@ -0,0 +1,107 @@
+package signature
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/openpgp"
+
+	"github.com/fabmation-gmbh/oima/pkg/errors"
+)
+
+// Status is the Result of a Signature Verification
+type Status string
+const (
+	StatusValid				Status	= "valid"				// Signature is valid and matches the Manifest Digest
+	StatusInvalid			Status	= "invalid"				// Signature is broken or could not be parsed
+	StatusWrongKey			Status	= "wrong-key"			// Signature was created with an untrusted Key
+	StatusDigestMismatch	Status	= "digest-mismatch"		// Signature is valid, but for another Manifest
+	StatusIdentityMismatch	Status	= "identity-mismatch"	// Signature is valid, but for another Docker Reference
+)
+
+// VerifyResult describes the Verification Result of a single Signature
+type VerifyResult struct {
+	Status			Status			// Result of the Verification
+	Signature		*Signature		// Parsed Signature (nil if the Signature could not be parsed)
+	Err				error			// Reason if the Status is not valid
+}
+
+// LoadKeyring reads all (armored or binary) Public Keys from @paths
+func LoadKeyring(paths []string) (openpgp.EntityList, error) {
+	var keyring openpgp.EntityList
+
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(os.ExpandEnv(path))
+		if err != nil { return nil, err }
+
+		entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+		if err != nil {
+			// try binary Keyring
+			entities, err = openpgp.ReadKeyRing(bytes.NewReader(data))
+			if err != nil { return nil, fmt.Errorf("unable to read Keyring '%s': %s", path, err.Error()) }
+		}
+
+		keyring = append(keyring, entities...)
+	}
+
+	return keyring, nil
+}
+
+// Verify checks if the Signature @data was created by a Key of @keyring
+// and if the signed Manifest Digest matches @digest
+func Verify(data []byte, keyring openpgp.KeyRing, digest string) *VerifyResult {
+	md, err := openpgp.ReadMessage(bytes.NewReader(data), keyring, nil, nil)
+	if err != nil { return &VerifyResult{ Status: StatusInvalid, Err: errors.NewInvalidSignatureErrorMsg(err.Error()) } }
+
+	if !md.IsSigned {
+		return &VerifyResult{ Status: StatusInvalid, Err: errors.NewInvalidSignatureErrorMsg("OpenPGP Message is not signed") }
+	}
+
+	// the Signature is checked after the whole Body was read
+	body, err := ioutil.ReadAll(md.UnverifiedBody)
+	if err != nil { return &VerifyResult{ Status: StatusInvalid, Err: errors.NewInvalidSignatureErrorMsg(err.Error()) } }
+
+	sig, err := parsePayload(body)
+	if err != nil { return &VerifyResult{ Status: StatusInvalid, Err: err } }
+	sig.KeyID = FormatKeyID(md.SignedByKeyId)
+
+	if md.SignedBy == nil {
+		return &VerifyResult{
+			Status:    StatusWrongKey,
+			Signature: sig,
+			Err:       errors.NewInvalidSignatureErrorMsg(fmt.Sprintf("Key %s is not in the Keyring", sig.KeyID)),
+		}
+	}
+
+	if md.SignatureError != nil {
+		return &VerifyResult{ Status: StatusInvalid, Signature: sig, Err: md.SignatureError }
+	}
+
+	if sig.ManifestDigest != digest {
+		return &VerifyResult{
+			Status:    StatusDigestMismatch,
+			Signature: sig,
+			Err:       errors.NewInvalidSignatureErrorMsg(fmt.Sprintf("Signed Digest %s does not match %s", sig.ManifestDigest, digest)),
+		}
+	}
+
+	return &VerifyResult{ Status: StatusValid, Signature: sig }
+}
+
+// CheckIdentity changes the Status of a valid @result to StatusIdentityMismatch if the
+// signed Docker Reference is not one of @references (eg. the Tag and all its Aliases),
+// so a Signature of another Repository is never accepted
+func CheckIdentity(result *VerifyResult, references []string) {
+	if result.Status != StatusValid { return }
+
+	for _, v := range references {
+		if result.Signature.DockerReference == v { return }
+	}
+
+	result.Status = StatusIdentityMismatch
+	result.Err = errors.NewInvalidSignatureErrorMsg(fmt.Sprintf("Signed Reference '%s' does not match '%s'",
+		result.Signature.DockerReference, strings.Join(references, "', '")))
+}
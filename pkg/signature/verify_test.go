@@ -0,0 +1,43 @@
+package signature
+
+import (
+	"crypto"
+	"testing"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/packet"
+)
+
+const digest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+
+func TestVerifyIdentity(t *testing.T) {
+	signer, err := openpgp.NewEntity("oima", "", "oima@example.com", &packet.Config{ DefaultHash: crypto.SHA256 })
+	if err != nil { t.Fatal(err) }
+
+	references := []string{ "docker.reg.local/team/app:1.0", "docker.reg.local/team/app:latest" }
+
+	tests := []struct {
+		reference	string
+		expected	Status
+	}{
+		{ "docker.reg.local/team/app:1.0", StatusValid },
+		{ "docker.reg.local/team/app:latest", StatusValid },
+		{ "docker.reg.local/team/other:1.0", StatusIdentityMismatch },
+		{ "evil.reg.local/team/app:1.0", StatusIdentityMismatch },
+	}
+
+	for _, test := range tests {
+		payload, err := NewPayload(test.reference, digest, "oima")
+		if err != nil { t.Fatal(err) }
+
+		data, err := Sign(payload, signer)
+		if err != nil { t.Fatal(err) }
+
+		result := Verify(data, openpgp.EntityList{ signer }, digest)
+		CheckIdentity(result, references)
+
+		if result.Status != test.expected {
+			t.Errorf("Reference '%s': expected %s, got %s (%v)", test.reference, test.expected, result.Status, result.Err)
+		}
+	}
+}
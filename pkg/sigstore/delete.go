@@ -0,0 +1,37 @@
+package sigstore
+
+import "context"
+
+// DeleteSignature deletes the Signature @name of @image@@digest from @store and moves all
+// following Signatures down (signature-3 becomes signature-2, ...), because containers/image
+// (and the HTTPStore) probe signature-1, signature-2, ... and stop at the first missing one,
+// so a Gap would hide all following Signatures.
+// The Signatures are moved before the last one is deleted, so there is never a Gap.
+func DeleteSignature(ctx context.Context, store SignatureStore, image string, digest string, name string) error {
+	signatures, err := store.List(ctx, image, digest)
+	if err != nil { return err }
+	SortSignatureNames(signatures)
+
+	var found bool
+	var following []string
+	for _, v := range signatures {
+		if v == name { found = true }
+		if signatureIndex(v) > signatureIndex(name) { following = append(following, v) }
+	}
+
+	// let the Store report the missing Signature
+	if !found { return store.Delete(ctx, image, digest, name) }
+
+	target := name
+	for _, v := range following {
+		data, err := store.Read(ctx, image, digest, v)
+		if err != nil { return err }
+
+		err = store.Write(ctx, image, digest, target, data)
+		if err != nil { return err }
+
+		target = v
+	}
+
+	return store.Delete(ctx, image, digest, target)
+}
@@ -0,0 +1,64 @@
+package sigstore
+
+import (
+	"context"
+	nethttp "net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const (
+	image	= "docker.reg.local/team/app"
+	digest	= "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+)
+
+func TestDeleteSignature(t *testing.T) {
+	ctx := context.Background()
+	store := &FileStore{ Dir: t.TempDir() }
+
+	for index, data := range []string{ "first", "second", "third" } {
+		err := store.Write(ctx, image, digest, SignatureName(index + 1), []byte(data))
+		if err != nil { t.Fatal(err) }
+	}
+
+	// delete the Signature in the Middle
+	if err := DeleteSignature(ctx, store, image, digest, SignatureName(2)); err != nil { t.Fatal(err) }
+
+	names, err := store.List(ctx, image, digest)
+	if err != nil { t.Fatal(err) }
+
+	expected := []string{ SignatureName(1), SignatureName(2) }
+	if !reflect.DeepEqual(names, expected) { t.Fatalf("expected %v, got %v", expected, names) }
+
+	for name, content := range map[string]string{ SignatureName(1): "first", SignatureName(2): "third" } {
+		data, err := store.Read(ctx, image, digest, name)
+		if err != nil { t.Fatal(err) }
+
+		if string(data) != content { t.Errorf("expected '%s' in %s, got '%s'", content, name, data) }
+	}
+
+	// Consumers which probe the Signatures (containers/image, HTTPStore) still find the following Signature
+	server := httptest.NewServer(nethttp.FileServer(nethttp.Dir(store.Dir)))
+	defer server.Close()
+
+	httpStore := &HTTPStore{ URL: server.URL }
+	if err := httpStore.Init(ctx); err != nil { t.Fatal(err) }
+
+	names, err = httpStore.List(ctx, image, digest)
+	if err != nil { t.Fatal(err) }
+
+	if !reflect.DeepEqual(names, expected) { t.Errorf("expected %v from the HTTP Store, got %v", expected, names) }
+
+	// the last Signature is just deleted
+	if err := DeleteSignature(ctx, store, image, digest, SignatureName(2)); err != nil { t.Fatal(err) }
+
+	names, err = store.List(ctx, image, digest)
+	if err != nil { t.Fatal(err) }
+
+	if !reflect.DeepEqual(names, []string{ SignatureName(1) }) { t.Errorf("expected only %s, got %v", SignatureName(1), names) }
+
+	if err := DeleteSignature(ctx, store, image, digest, SignatureName(5)); err == nil {
+		t.Errorf("expected an Error for a missing Signature")
+	}
+}
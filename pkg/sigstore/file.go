@@ -0,0 +1,127 @@
+package sigstore
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+)
+
+// FileStore is a SignatureStore which stores the Signatures in a
+// local Directory (eg. 'file:///var/lib/containers/sigstore')
+type FileStore struct {
+	Dir		string		// Root Directory of the Signature Store
+}
+
+func (f *FileStore) Init(ctx context.Context) error {
+	info, err := os.Stat(f.Dir)
+	if err != nil {
+		Log.Errorf("Unable to access Signature Store Directory '%s': %s", f.Dir, err.Error())
+		return err
+	}
+
+	if !info.IsDir() {
+		Log.Errorf("Signature Store '%s' is not a Directory", f.Dir)
+		return os.ErrInvalid
+	}
+
+	Log.Debugf("File Signature Store initialized (%s)", f.Dir)
+
+	return nil
+}
+
+func (f *FileStore) Exists(ctx context.Context, image string, digest string) (bool, error) {
+	signatures, err := f.List(ctx, image, digest)
+	if err != nil { return false, err }
+
+	return len(signatures) > 0, nil
+}
+
+func (f *FileStore) List(ctx context.Context, image string, digest string) ([]string, error) {
+	if err := ctx.Err(); err != nil { return nil, err }
+
+	entries, err := ioutil.ReadDir(filepath.Join(f.Dir, filepath.FromSlash(DigestPath(image, digest))))
+	if err != nil {
+		if os.IsNotExist(err) { return nil, nil }
+		return nil, err
+	}
+
+	var signatures []string
+	for _, v := range entries {
+		if !v.IsDir() && IsSignatureName(v.Name()) { signatures = append(signatures, v.Name()) }
+	}
+	SortSignatureNames(signatures)
+
+	return signatures, nil
+}
+
+func (f *FileStore) Read(ctx context.Context, image string, digest string, name string) ([]byte, error) {
+	if err := ctx.Err(); err != nil { return nil, err }
+
+	return ioutil.ReadFile(f.path(image, digest, name))
+}
+
+func (f *FileStore) Write(ctx context.Context, image string, digest string, name string, data []byte) error {
+	if err := ctx.Err(); err != nil { return err }
+
+	path := f.path(image, digest, name)
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil { return err }
+
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+func (f *FileStore) Delete(ctx context.Context, image string, digest string, name string) error {
+	if err := ctx.Err(); err != nil { return err }
+
+	path := f.path(image, digest, name)
+
+	err := os.Remove(path)
+	if err != nil { return err }
+
+	// remove the Digest Directory if it's empty now
+	// (os.Remove() fails on non-empty Directories)
+	_ = os.Remove(filepath.Dir(path))
+
+	return nil
+}
+
+func (f *FileStore) ListDigests(ctx context.Context, prefix string) ([]DigestRef, error) {
+	var refs []DigestRef
+	found := make(map[DigestRef]bool)
+
+	root := filepath.Join(f.Dir, filepath.FromSlash(prefix))
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root { return filepath.SkipDir }
+			return err
+		}
+		if err := ctx.Err(); err != nil { return err }
+		if info.IsDir() { return nil }
+
+		rel, err := filepath.Rel(f.Dir, path)
+		if err != nil { return err }
+
+		image, digest, _, ok := ParseSignaturePath(filepath.ToSlash(rel))
+		if !ok { return nil }
+
+		ref := DigestRef{ Image: image, Digest: digest }
+		if !found[ref] {
+			found[ref] = true
+			refs = append(refs, ref)
+		}
+
+		return nil
+	})
+	if err != nil { return nil, err }
+
+	return refs, nil
+}
+
+// path returns the Path of the Signature File @name
+func (f *FileStore) path(image string, digest string, name string) string {
+	return filepath.Join(f.Dir, filepath.FromSlash(SignaturePath(image, digest, name)))
+}
@@ -0,0 +1,95 @@
+package sigstore
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+
+	. "github.com/fabmation-gmbh/oima/internal/log"
+	"github.com/fabmation-gmbh/oima/pkg/config"
+	"github.com/fabmation-gmbh/oima/pkg/errors"
+	"github.com/fabmation-gmbh/oima/pkg/http"
+)
+
+// HTTPStore is a read-only SignatureStore which reads the Signatures from
+// a Lookaside Web-Server (eg. 'https://sigstore.example.com/sigstore')
+type HTTPStore struct {
+	URL			string					// Base URL of the Signature Store
+	Transport	config.TransportConf	// TLS, Proxy and Timeouts of the Web-Server
+
+	client		*nethttp.Client			// Shared by all Requests
+}
+
+func (h *HTTPStore) Init(ctx context.Context) error {
+	client, err := http.NewClient(h.Transport)
+	if err != nil { return err }
+	h.client = client
+
+	Log.Debugf("HTTP Signature Store initialized (%s)", h.URL)
+
+	return nil
+}
+
+func (h *HTTPStore) Exists(ctx context.Context, image string, digest string) (bool, error) {
+	_, found, err := h.get(ctx, image, digest, SignatureName(1))
+
+	return found, err
+}
+
+// List() probes signature-1, signature-2, ... until the first one is missing,
+// because a Web-Server can't list the Content of a Directory.
+// This is the same Behaviour as containers/image.
+func (h *HTTPStore) List(ctx context.Context, image string, digest string) ([]string, error) {
+	var signatures []string
+
+	for index := 1; ; index++ {
+		_, found, err := h.get(ctx, image, digest, SignatureName(index))
+		if err != nil { return nil, err }
+		if !found { break }
+
+		signatures = append(signatures, SignatureName(index))
+	}
+
+	return signatures, nil
+}
+
+func (h *HTTPStore) Read(ctx context.Context, image string, digest string, name string) ([]byte, error) {
+	data, found, err := h.get(ctx, image, digest, name)
+	if err != nil { return nil, err }
+	if !found { return nil, errors.NewSignatureNotFoundError() }
+
+	return data, nil
+}
+
+func (h *HTTPStore) Write(ctx context.Context, image string, digest string, name string, data []byte) error {
+	return errors.NewSignatureStoreReadOnlyError()
+}
+
+func (h *HTTPStore) Delete(ctx context.Context, image string, digest string, name string) error {
+	return errors.NewSignatureStoreReadOnlyError()
+}
+
+// ListDigests() is not possible, because a Web-Server can't list the Content of a Directory
+func (h *HTTPStore) ListDigests(ctx context.Context, prefix string) ([]DigestRef, error) {
+	return nil, errors.NewSignatureStoreNotSupportedErrorMsg("Listing all Signatures is not supported by HTTP Signature Stores")
+}
+
+// get downloads the Signature @name and returns false if it does not exists
+func (h *HTTPStore) get(ctx context.Context, image string, digest string, name string) ([]byte, bool, error) {
+	uri := fmt.Sprintf("%s/%s", h.URL, SignaturePath(image, digest, name))
+
+	resp, err := http.Retry.Get(ctx, http.NewRestyClient(h.client).R(), uri)
+	if err != nil {
+		Log.Errorf("Error while getting Signature '%s': %s", uri, err.Error())
+		return nil, false, err
+	}
+
+	switch resp.StatusCode() {
+	case 200:
+		return resp.Body(), true, nil
+	case 403, 404:
+		return nil, false, nil
+	default:
+		return nil, false, errors.NewSignatureStoreRequestError(uri, resp.StatusCode())
+	}
+}
@@ -0,0 +1,49 @@
+package sigstore
+
+import "context"
+
+// SignatureStore is a Storage for (simple signing) Signatures which uses the
+// same Layout as the 'sigstore' of containers/image (CRI-O, Podman, Skopeo):
+//		<registry>/<image>@<algo>=<digest>/signature-<n>
+//
+// @image always describes the Path of the Image in the Store (see ImagePath()),
+// @digest is the Docker Content Digest of the Manifest (eg. 'sha256:92c7...')
+// and @name is the Name of the Signature Object (eg. 'signature-1').
+// All Requests are canceled when @ctx is done.
+type SignatureStore interface {
+	// Initializes the Signature Store
+	Init(ctx context.Context)					error
+
+	// Returns true if at least one Signature exists for @image@@digest
+	Exists(ctx context.Context, image string, digest string)		(bool, error)
+
+	// Lists the Names of all Signatures of @image@@digest
+	List(ctx context.Context, image string, digest string)		([]string, error)
+
+	// Returns the Content of the Signature @name
+	Read(ctx context.Context, image string, digest string, name string)		([]byte, error)
+
+	// Writes (or overwrites) the Signature @name
+	Write(ctx context.Context, image string, digest string, name string, data []byte)	error
+
+	// Deletes the Signature @name
+	Delete(ctx context.Context, image string, digest string, name string)	error
+
+	// Lists all Digests (of all Images) below @prefix (eg. 'docker.reg.local')
+	// for which at least one Signature exists
+	ListDigests(ctx context.Context, prefix string)		([]DigestRef, error)
+}
+
+// DigestRef describes the Signatures Directory of a single Manifest
+//		<Image>@<Digest>/
+type DigestRef struct {
+	Image		string		// Path of the Image in the Store (eg. 'docker.reg.local/nginx')
+	Digest		string		// Docker Content Digest (eg. 'sha256:92c7...')
+}
+
+// ReadOnly returns true if Signatures can not be written to or deleted from @store
+// (a HTTP Lookaside Server)
+func ReadOnly(store SignatureStore) bool {
+	_, ok := store.(*HTTPStore)
+	return ok
+}
@@ -0,0 +1,91 @@
+package sigstore
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// This File contains the Functions which describes the Layout of the
+// 'sigstore', they could be used by all SignatureStore Implementations
+
+// PrepareRegPath() checks and manipulates the Registry URI
+// so that it's usable as Path in the Signature Store
+// Example:
+//		Input:		https://docker.internal.int/
+//		Output:		docker.internal.int
+func PrepareRegPath(uri string) string {
+	r, _ := regexp.Compile("http(s)?://")					// remove 'https://' or 'http://'
+	return strings.ReplaceAll(r.ReplaceAllString(uri, ""), "/", "") // remove '/' Postfix
+}
+
+// ImagePath() returns the Path of an Image in the Signature Store
+// Example:
+//		Input:		https://docker.internal.int/, library/nginx
+//		Output:		docker.internal.int/library/nginx
+func ImagePath(registryURI string, image string) string {
+	return fmt.Sprintf("%s/%s", PrepareRegPath(registryURI), image)
+}
+
+// DigestPath() returns the Path of the Directory which contains
+// all Signatures of the Manifest @digest
+// Example:
+//		Input:		docker.internal.int/nginx, sha256:92c7f9c9...
+//		Output:		docker.internal.int/nginx@sha256=92c7f9c9...
+func DigestPath(image string, digest string) string {
+	return fmt.Sprintf("%s@%s", image, strings.ReplaceAll(digest, ":", "="))
+}
+
+// SignaturePath() returns the Path of the Signature @name
+func SignaturePath(image string, digest string, name string) string {
+	return fmt.Sprintf("%s/%s", DigestPath(image, digest), name)
+}
+
+// SignatureName() returns the Name of the @index'th Signature (starting at 1)
+func SignatureName(index int) string {
+	return fmt.Sprintf("signature-%d", index)
+}
+
+// IsSignatureName() returns true if @name is a valid Signature Name
+func IsSignatureName(name string) bool {
+	var index int
+	n, err := fmt.Sscanf(name, "signature-%d", &index)
+
+	return err == nil && n == 1 && SignatureName(index) == name && index > 0
+}
+
+// SortSignatureNames() sorts Signature Names by their Index
+// (signature-2 before signature-10)
+func SortSignatureNames(names []string) {
+	sort.Slice(names, func(i, j int) bool { return signatureIndex(names[i]) < signatureIndex(names[j]) })
+}
+
+// signatureIndex() returns the Index of the Signature @name
+func signatureIndex(name string) int {
+	var index int
+	_, _ = fmt.Sscanf(name, "signature-%d", &index)
+
+	return index
+}
+
+// ParseSignaturePath() is the Counterpart of SignaturePath(), it splits
+// the Path of a Signature into the Image Path, the Digest and the Signature Name
+// Example:
+//		Input:		docker.internal.int/nginx@sha256=92c7f9c9.../signature-1
+//		Output:		docker.internal.int/nginx, sha256:92c7f9c9..., signature-1
+func ParseSignaturePath(path string) (image string, digest string, name string, ok bool) {
+	i := strings.LastIndex(path, "/")
+	if i < 0 { return "", "", "", false }
+
+	name = path[i+1:]
+	if !IsSignatureName(name) { return "", "", "", false }
+
+	j := strings.LastIndex(path[:i], "@")
+	if j < 0 { return "", "", "", false }
+
+	image, digest = path[:j], strings.Replace(path[j+1:i], "=", ":", 1)
+	if len(image) == 0 || !strings.Contains(digest, ":") { return "", "", "", false }
+
+	return image, digest, name, true
+}
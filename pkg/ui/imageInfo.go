@@ -10,11 +10,14 @@ import (
 	"github.com/gizak/termui/v3/widgets"
 	rw "github.com/mattn/go-runewidth"
 	"image"
+	"sort"
+	"strings"
 
 	"github.com/fabmation-gmbh/oima/internal"
 	. "github.com/fabmation-gmbh/oima/internal/log"
 	"github.com/fabmation-gmbh/oima/pkg/config"
 	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
+	"github.com/fabmation-gmbh/oima/pkg/signature"
 )
 
 var conf config.Configuration
@@ -38,6 +41,23 @@ type ImageInfo struct {
 	// this is needed because BaseImage contains
 	// the Function to delete Signatures
 	ImagePtr			*rt.BaseImage
+
+	// signatureRows caches the Rows describing the parsed Signatures
+	// of a Tag (Key: Content Digest), so that they are only
+	// downloaded once and not on every Draw() call
+	signatureRows		map[string][]string
+
+	// manifestRows caches the Rows describing the Manifests and Image
+	// Configurations of a Tag (Key: Content Digest)
+	manifestRows		map[string][]string
+
+	// message is shown at the Top of the Info Box (eg. an Error while deleting),
+	// it is removed when another Tag is selected
+	message				string
+
+	// pendingDelete describes the Deletion (eg. 'manifest:<digest>') which has to be
+	// confirmed (by pressing the Key again), because other Tags point to the Digest, too
+	pendingDelete		string
 }
 
 func NewImageInfo() *ImageInfo {
@@ -50,6 +70,8 @@ func NewImageInfo() *ImageInfo {
 		WrapText:         false,
 		TextStyle:        Theme.List.Text,
 		SelectedRowStyle: Theme.List.Text,
+		signatureRows:    make(map[string][]string),
+		manifestRows:     make(map[string][]string),
 	}
 }
 
@@ -115,6 +137,8 @@ func (ii *ImageInfo) Draw(buf *Buffer) {
 // There is no need to set ii.topRow, as this will be set automatically when drawn,
 // since if the selected item is off screen then the topRow variable will change accordingly.
 func (ii *ImageInfo) ScrollAmount(amount int) {
+	ii.resetMessage()
+
 	if len(*ii.Rows)-int(ii.SelectedRow) <= amount {
 		ii.SelectedRow = len(*ii.Rows) - 1
 	} else if int(ii.SelectedRow)+amount < 0 {
@@ -122,6 +146,9 @@ func (ii *ImageInfo) ScrollAmount(amount int) {
 	} else {
 		ii.SelectedRow += amount
 	}
+
+	// all Tags of the Image can be deleted
+	if ii.SelectedRow < 0 { ii.SelectedRow = 0 }
 }
 
 func (ii *ImageInfo) ScrollUp() {
@@ -139,6 +166,8 @@ func (ii *ImageInfo) ScrollDown() {
 }
 
 func (ii *ImageInfo) ScrollPageUp() {
+	ii.resetMessage()
+
 	// If an item is selected below top row, then go to the top row.
 	if ii.SelectedRow > ii.topRow {
 		ii.SelectedRow = ii.topRow
@@ -172,6 +201,7 @@ func (ii *ImageInfo) ScrollHalfPageDown() {
 }
 
 func (ii *ImageInfo) ScrollTop() {
+	ii.resetMessage()
 	ii.SelectedRow = 0
 
 	// update shown Tag Info
@@ -179,18 +209,59 @@ func (ii *ImageInfo) ScrollTop() {
 }
 
 func (ii *ImageInfo) ScrollBottom() {
+	ii.resetMessage()
 	ii.SelectedRow = len(*ii.Rows) - 1
+	if ii.SelectedRow < 0 { ii.SelectedRow = 0 }
 
 	// update shown Tag Info
 	ii.updateTagInfo()
 }
 
+// DeleteSignature deletes all Signatures of the selected Tag. The Signatures belong
+// to the Digest, so if other Tags point to it, too, the Deletion has to be confirmed.
 func (ii *ImageInfo) DeleteSignature() {
-	// delete Signature
-	(*ii.ImagePtr).DeleteSignature(&(*ii.Rows)[ii.SelectedRow])
+	if len(*ii.Rows) == 0 { return }
+
+	tag := &(*ii.Rows)[ii.SelectedRow]
+	if !ii.confirmDelete("signature:" + tag.ContentDigest, tag,
+		"the Signatures are shared by the Tags %s and are deleted for them, too! Press 'd' again to delete them.") { return }
+
+	// delete all Signatures of the Tag
+	err := (*ii.ImagePtr).DeleteSignature(uiContext, tag, rt.AllSignatures)
+	delete(ii.signatureRows, tag.ContentDigest)
+
+	// show the Error in the Info Box instead of exiting
+	ii.message = ""
+	if err != nil { ii.message = fmt.Sprintf("[Error while deleting Signature: %s](fg:red)", err.Error()) }
+
+	// update Tag Informations
+	ii.updateTagInfo()
+}
+
+// DeleteManifest deletes the Manifest of the selected Tag (and its Signatures).
+// If other Tags point to the same Manifest, they are deleted by the Registry, too,
+// so a Warning is shown first and the Deletion has to be confirmed.
+func (ii *ImageInfo) DeleteManifest() {
+	if len(*ii.Rows) == 0 { return }
+
+	tag := &(*ii.Rows)[ii.SelectedRow]
+	digest := tag.ContentDigest
+
+	if !ii.confirmDelete("manifest:" + digest, tag,
+		"the Tags %s point to the same Manifest and are deleted, too! Press 'x' again to delete it.") { return }
+
+	// the deleted Tags are removed from the Tag List of the Image
+	err := (*ii.ImagePtr).DeleteManifest(uiContext, tag)
+	delete(ii.signatureRows, digest)
+	delete(ii.manifestRows, digest)
+
+	ii.message = fmt.Sprintf("[Manifest %s deleted](fg:green)", digest)
+	if err != nil { ii.message = fmt.Sprintf("[Error while deleting Manifest: %s](fg:red)", err.Error()) }
+
+	if ii.SelectedRow >= len(*ii.Rows) { ii.SelectedRow = len(*ii.Rows) - 1 }
+	if ii.SelectedRow < 0 { ii.SelectedRow = 0 }
 
 	// update Tag Informations
-	(*ii.Rows)[ii.SelectedRow].S3SignFound = false
 	ii.updateTagInfo()
 }
 
@@ -199,27 +270,187 @@ func (ii *ImageInfo) DeleteSignature() {
 // updateTagInfo Updates the Info Box at the Bottom with
 //  new Informations about the (new) selected Tag
 func (ii *ImageInfo) updateTagInfo() {
-	if ii.ImageTagInfo != nil {
-		// get S3 Signature Status
+	if ii.ImageTagInfo != nil && len(*ii.Rows) == 0 {
+		ii.ImageTagInfo.Rows = []string{ii.message, "[No Tags found](fg:red)"}
+	} else if ii.ImageTagInfo != nil {
+		// get Signature Status
 		var s3SignatureStatus string
-		if !conf.S3.Enabled {
-			s3SignatureStatus = "[S3 Component Disabled](fg:red)"
+		if !(*ii.ImagePtr).SignatureStoreEnabled() {
+			s3SignatureStatus = "[Signature Store Disabled](fg:red)"
 		} else {
-			if (*ii.Rows)[ii.SelectedRow].S3SignFound {
-				s3SignatureStatus = "[Signature found](fg:green)"
+			if count := (*ii.Rows)[ii.SelectedRow].SignatureCount(); count > 0 {
+				s3SignatureStatus = fmt.Sprintf("[%d Signature(s) found](fg:green) (%s)", count,
+					strings.Join((*ii.Rows)[ii.SelectedRow].Signatures, ", "))
 			} else {
 				s3SignatureStatus = "[Signature not found](fg:red)"
 			}
 		}
 
+		// get the other Tags pointing to the same Digest (they share the Signatures)
+		aliases := "-"
+		if names := (*ii.ImagePtr).Aliases(&(*ii.Rows)[ii.SelectedRow]); len(names) > 0 {
+			var list []string
+			for _, v := range names { list = append(list, string(v)) }
+			aliases = strings.Join(list, ", ")
+		}
+
+		// get Notary Trust Data Status
+		var notarySignatureStatus string
+		if !conf.Notary.Enabled {
+			notarySignatureStatus = "[Notary Component Disabled](fg:red)"
+		} else {
+			if (*ii.Rows)[ii.SelectedRow].NotarySignFound {
+				notarySignatureStatus = "[Trust Data found](fg:green)"
+			} else {
+				notarySignatureStatus = "[Trust Data not found](fg:red)"
+			}
+		}
+
 		ii.ImageTagInfo.Rows = []string{
-			"",
+			ii.message,
 			fmt.Sprintf("[Tag Name:](mod:bold,fg:clear)              %s", (*ii.Rows)[ii.SelectedRow].Name),
 			fmt.Sprintf("[Content Digest:](mod:bold,fg:clear)        %s", (*ii.Rows)[ii.SelectedRow].ContentDigest),
-			fmt.Sprintf("[Signature found in S3:](mod:bold,fg:clear) %s", s3SignatureStatus),
+			fmt.Sprintf("[Aliases:](mod:bold,fg:clear)               %s", aliases),
+			fmt.Sprintf("[Media Type:](mod:bold,fg:clear)            %s", (*ii.Rows)[ii.SelectedRow].MediaType),
+			fmt.Sprintf("[Signature found:](mod:bold,fg:clear)       %s", s3SignatureStatus),
+			fmt.Sprintf("[Signed in Notary:](mod:bold,fg:clear)      %s", notarySignatureStatus),
 			"[](fg:clear)",
 		}
+
+		// add the Platform Manifests of an Image Index
+		ii.ImageTagInfo.Rows = append(ii.ImageTagInfo.Rows, ii.getPlatformRows(&(*ii.Rows)[ii.SelectedRow])...)
+
+		// add the Manifest and Image Configuration (Created, Size, Layers, Labels)
+		ii.ImageTagInfo.Rows = append(ii.ImageTagInfo.Rows, ii.getManifestRows(&(*ii.Rows)[ii.SelectedRow])...)
+
+		// add the Informations of all Signatures
+		ii.ImageTagInfo.Rows = append(ii.ImageTagInfo.Rows, ii.getSignatureRows(&(*ii.Rows)[ii.SelectedRow])...)
 	} else {
 		Log.Warningf("ImageInfo.ImageTagInfo is nil!")
 	}
-}
\ No newline at end of file
+}
+
+// resetMessage removes the Message shown in the Info Box and
+// cancels a pending Deletion (another Tag gets selected)
+func (ii *ImageInfo) resetMessage() {
+	ii.message = ""
+	ii.pendingDelete = ""
+}
+
+// confirmDelete returns true if the Deletion @key of Tag @tag can be executed. If other Tags
+// (Aliases) point to the Digest of @tag, the Warning @warning (with the Aliases) is shown
+// and false is returned, until the Deletion is requested again.
+func (ii *ImageInfo) confirmDelete(key string, tag *rt.Tag, warning string) bool {
+	aliases := (*ii.ImagePtr).Aliases(tag)
+	if len(aliases) == 0 || ii.pendingDelete == key {
+		ii.pendingDelete = ""
+		return true
+	}
+
+	var names []string
+	for _, v := range aliases { names = append(names, string(v)) }
+
+	ii.pendingDelete = key
+	ii.message = "[Warning: " + fmt.Sprintf(warning, strings.Join(names, ", ")) + "](fg:yellow)"
+	ii.updateTagInfo()
+
+	return false
+}
+
+// getPlatformRows returns the Rows with the Digest and the Signature Status
+// of all Platform Manifests of the Tag @tag (if it points to an Image Index)
+func (ii *ImageInfo) getPlatformRows(tag *rt.Tag) []string {
+	if !tag.IsIndex() { return nil }
+
+	rows := []string{"[Platforms:](mod:bold,fg:clear)"}
+	for _, p := range tag.Platforms {
+		status := "[not signed](fg:red)"
+		if len(p.Signatures) > 0 { status = fmt.Sprintf("[%d Signature(s)](fg:green)", len(p.Signatures)) }
+
+		rows = append(rows, fmt.Sprintf("  [%-20s](fg:clear) %s  %s", p.Platform, p.Digest, status))
+	}
+
+	return append(rows, "[](fg:clear)")
+}
+
+// getManifestRows returns the Rows with the Informations of the Manifests (one per Platform)
+// of the Tag @tag. The Manifests and Image Configurations are downloaded on the first call.
+func (ii *ImageInfo) getManifestRows(tag *rt.Tag) []string {
+	if rows, ok := ii.manifestRows[tag.ContentDigest]; ok { return rows }
+
+	manifests, err := (*ii.ImagePtr).InspectTag(uiContext, tag)
+	if err != nil {
+		rows := []string{fmt.Sprintf("[Error while inspecting Manifest: %s](fg:red)", err.Error()), "[](fg:clear)"}
+		ii.manifestRows[tag.ContentDigest] = rows
+
+		return rows
+	}
+
+	var rows []string
+	for _, m := range manifests {
+		created := "-"
+		if !m.Created.IsZero() { created = m.Created.String() }
+
+		rows = append(rows,
+			fmt.Sprintf("[Image %s:](mod:bold,fg:clear)", m.Platform),
+			fmt.Sprintf("  [Created:](fg:clear)          %s", created),
+			fmt.Sprintf("  [Architecture/OS:](fg:clear)  %s/%s", m.Architecture, m.OS),
+			fmt.Sprintf("  [Size:](fg:clear)             %s", rt.FormatSize(m.Size)),
+			fmt.Sprintf("  [Layers:](fg:clear)           %d", len(m.Layers)),
+		)
+
+		for _, l := range m.Layers {
+			rows = append(rows, fmt.Sprintf("    %s  %s", l.Digest, rt.FormatSize(l.Size)))
+		}
+
+		keys := make([]string, 0, len(m.Labels))
+		for k := range m.Labels { keys = append(keys, k) }
+		sort.Strings(keys)
+
+		rows = append(rows, fmt.Sprintf("  [Labels:](fg:clear)           %d", len(keys)))
+		for _, k := range keys { rows = append(rows, fmt.Sprintf("    %s=%s", k, m.Labels[k])) }
+	}
+
+	rows = append(rows, "[](fg:clear)")
+	ii.manifestRows[tag.ContentDigest] = rows
+
+	return rows
+}
+
+// getSignatureRows returns the Rows with the Informations of all Signatures
+// of the Tag @tag. The Signatures are downloaded and parsed on the first call.
+func (ii *ImageInfo) getSignatureRows(tag *rt.Tag) []string {
+	if rows, ok := ii.signatureRows[tag.ContentDigest]; ok { return rows }
+
+	var rows []string
+	for _, name := range tag.Signatures {
+		rows = append(rows, fmt.Sprintf("[%s:](mod:bold,fg:clear)", name))
+
+		data, err := (*ii.ImagePtr).ReadSignature(uiContext, tag, name)
+		if err != nil {
+			rows = append(rows, fmt.Sprintf("  [Error while downloading Signature: %s](fg:red)", err.Error()))
+			continue
+		}
+
+		sig, err := signature.Parse(data)
+		if err != nil {
+			rows = append(rows, fmt.Sprintf("  [Error while parsing Signature: %s](fg:red)", err.Error()))
+			continue
+		}
+
+		timestamp := "-"
+		if !sig.Timestamp.IsZero() { timestamp = sig.Timestamp.String() }
+
+		rows = append(rows,
+			fmt.Sprintf("  [Manifest Digest:](fg:clear)  %s", sig.ManifestDigest),
+			fmt.Sprintf("  [Docker Reference:](fg:clear) %s", sig.DockerReference),
+			fmt.Sprintf("  [Creator:](fg:clear)          %s", sig.Creator),
+			fmt.Sprintf("  [Timestamp:](fg:clear)        %s", timestamp),
+			fmt.Sprintf("  [Key ID:](fg:clear)           %s", sig.KeyID),
+		)
+	}
+
+	ii.signatureRows[tag.ContentDigest] = rows
+
+	return rows
+}
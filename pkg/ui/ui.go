@@ -1,17 +1,20 @@
 package ui
 
 import (
+	"context"
 	"fmt"
-	"github.com/awnumar/memguard"
 	ui "github.com/gizak/termui/v3"
 	"github.com/gizak/termui/v3/widgets"
 
+	"github.com/fabmation-gmbh/oima/internal"
 	. "github.com/fabmation-gmbh/oima/internal/log"
 	"github.com/fabmation-gmbh/oima/pkg/registry"
 	rt "github.com/fabmation-gmbh/oima/pkg/registry/interfaces"
 )
 
-var dockerReg registry.DockerRegistry
+var uiContext context.Context					// Context of all Requests sent by the UI
+var dockerRegs []*registry.DockerRegistry		// all Registries shown in the UI
+var fetchErrors map[*registry.DockerRegistry]error	// Errors while Fetching a Registry (shown in the Tree)
 var tree []*TreeNode
 var grid *ui.Grid
 var repoImageTree *Tree
@@ -24,12 +27,18 @@ type nodeValue string
 
 func (nv nodeValue) String() string { return string(nv) }
 
-func StartUI() {
-	initRegistry()
+// StartUI starts the UI for the Registry @registryName,
+// or for all configured Registries if @registryName is empty.
+// Returns an Error if no Registry could be initialized.
+func StartUI(ctx context.Context, registryName string) error {
+	uiContext = ctx
+
+	err := initRegistries(registryName)
+	if err != nil { return err }
 
 	if err := ui.Init(); err != nil {
-		Log.Fatalf("failed to initialize termui: %v", err)
-		memguard.SafeExit(1)
+		Log.Errorf("failed to initialize termui: %v", err)
+		return err
 	}
 	defer ui.Close()
 
@@ -51,7 +60,7 @@ func StartUI() {
 		e := <-uiEvents
 		switch e.ID {
 		case "q", "<C-c>":
-			return
+			return nil
 		case "e", "E":
 			imageInfoUI = false
 
@@ -59,6 +68,8 @@ func StartUI() {
 			drawFunction()
 		case "d", "D":
 			if imageInfoUI { tagList.DeleteSignature() }
+		case "x", "X":
+			if imageInfoUI { tagList.DeleteManifest() }
 		case "j", "<Down>":
 			if imageInfoUI {
 				tagList.ScrollDown()
@@ -158,33 +169,58 @@ func drawFunction() {
 	ui.Render(grid)
 }
 
-// updateStats() Updates Registry Statistics
+// updateStats() Updates Registry Statistics (of all Registries)
 func updateStats() {
-	regStats := dockerReg.Stats()
+	var regStats rt.Stats
+
+	for _, dockerReg := range dockerRegs {
+		s := dockerReg.Stats()
+
+		regStats.Repos += s.Repos
+		regStats.Images += s.Images
+		regStats.Tags += s.Tags
+		regStats.S3Signatures += s.S3Signatures
+		regStats.NotarySignatures += s.NotarySignatures
+	}
 
 	stats.Rows = []string{
 		fmt.Sprintf("Repositories: %8d", regStats.Repos),
 		fmt.Sprintf("Images:       %8d", regStats.Images),
 		fmt.Sprintf("Tags:         %8d", regStats.Tags),
 		fmt.Sprintf("S3Signatures: %8d", regStats.S3Signatures),
+		fmt.Sprintf("Notary:       %8d", regStats.NotarySignatures),
 	}
 }
 
 func getTree() []*TreeNode {
-	// get List of Repositories
-	repos := dockerReg.ListRepositories()
+	var nodes []*TreeNode
+
+	// add one Node per Registry
+	for _, dockerReg := range dockerRegs {
+		value := fmt.Sprintf("%s (%s)", dockerReg.Name, dockerReg.URI)
+		if err := fetchErrors[dockerReg]; err != nil { value += fmt.Sprintf(" [incomplete: %s]", err.Error()) }
+
+		nodes = append(nodes, &TreeNode{
+			Value:    nodeValue(value),
+			Expanded: len(dockerRegs) == 1,
+			Nodes:    getRegistryTree(dockerReg),
+		})
+	}
+
+	return nodes
+}
+
+// getRegistryTree returns the Repository Nodes (with Image Nodes) of @dockerReg
+func getRegistryTree(dockerReg *registry.DockerRegistry) []*TreeNode {
+	// get List of (already fetched) Repositories
+	repos := dockerReg.Repos
 	Log.Debugf("Registry %s has %d Repositories", dockerReg.URI, len(repos))
 
 	// create Tree
-	var nodes []*TreeNode
 	var _nodes []*TreeNode
 
 	for _, v := range repos {
-		images, err := v.ListImages()
-		if err != nil {
-			Log.Criticalf("Error while getting List of Images in Repo %s: %s", v.Name, err.Error())
-			memguard.SafeExit(1)
-		}
+		images := v.Images
 
 		var imageEntries []*TreeNode
 
@@ -211,15 +247,7 @@ func getTree() []*TreeNode {
 		_nodes = append(_nodes, &repoEntry)
 	}
 
-	nodes = []*TreeNode{
-		{
-			Value:    nodeValue(dockerReg.URI),
-			Expanded: true,
-			Nodes:    _nodes,
-		},
-	}
-
-	return nodes
+	return _nodes
 }
 
 func initGrid() {
@@ -265,24 +293,48 @@ func showImageInfo(i *registry.Image) {
 
 /// >>>>> internal Functions <<<<<
 
-// Initialize Docker Registry and handle Errors
-// TODO: better handle custom Errors!
-func initRegistry() {
-	err := dockerReg.Init()
-	if err != nil {
-		Log.Panicf("Error while Initialize DockerRegistry: %s", err.Error())
+// Initialize the Docker Registry @registryName (or all configured Registries)
+// and handle Errors. Registries which can not be initialized are skipped,
+// an Error is only returned if no Registry is left.
+func initRegistries(registryName string) error {
+	var names []string
+	var lastErr error
+
+	fetchErrors = make(map[*registry.DockerRegistry]error)
+
+	if len(registryName) > 0 {
+		names = []string{registryName}
+	} else {
+		for _, reg := range internal.GetConfig().GetRegistries() { names = append(names, reg.Name) }
+	}
+
+	for _, name := range names {
+		dockerReg := &registry.DockerRegistry{ Name: name }
+
+		err := dockerReg.Init(uiContext)
+		if err != nil {
+			Log.Errorf("Error while Initialize DockerRegistry '%s': %s", name, err.Error())
+			lastErr = err
+			continue
+		}
+
+		// Fetch All Informations from Docker Registry
+		registryFetch(dockerReg)
+
+		dockerRegs = append(dockerRegs, dockerReg)
 	}
 
-	// Fetch All Informations from Docker Registry
-	registryFetch()
+	if len(dockerRegs) == 0 && lastErr != nil { return lastErr }
+
+	return nil
 }
 
-// Fetch all Informations form the Docker Registry and handle Errors
-// TODO: better handle custom Errors!
-func registryFetch() {
-	err := dockerReg.FetchAll()
+// Fetch all Informations form the Docker Registry and handle Errors.
+// The Repositories fetched before the Error are still shown.
+func registryFetch(dockerReg *registry.DockerRegistry) {
+	err := dockerReg.FetchAll(uiContext)
 	if err != nil {
-		Log.Fatalf("Error while Fetching All Informations from Registry '%s': %s", dockerReg.URI, err.Error())
-		memguard.SafeExit(1)
+		Log.Errorf("Error while Fetching All Informations from Registry '%s': %s", dockerReg.URI, err.Error())
+		fetchErrors[dockerReg] = err
 	}
 }